@@ -10,6 +10,8 @@ import (
 
 type button struct {
 	Focusable
+	Nameable
+	Labelable
 	lines      []string
 	width      int
 	height     int
@@ -41,19 +43,23 @@ func (btn *button) Height() size.T {
 }
 
 func (btn *button) Render(canvas wind.Canvas) {
+	defer btn.drawLabel(canvas)
+	fg := term.ColorDefault
 	bg := term.ColorDefault
-	if btn.IsFocused() {
+	if btn.IsDisabled() {
+		fg = term.ColorBlack | term.AttrBold
+	} else if btn.IsFocused() {
 		bg = term.ColorRed
 	}
 	for y, row := range btn.lines {
 		for x, c := range row {
-			canvas.Draw(x, y, c, 0, uint16(bg))
+			canvas.Draw(x, y, c, uint16(fg), uint16(bg))
 		}
 	}
 }
 
 func (btn *button) Control(flow *control.Flow) {
-	if btn.Controller != nil {
+	if btn.Controller != nil && !btn.IsDisabled() {
 		btn.Controller(flow)
 	}
 }
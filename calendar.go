@@ -0,0 +1,367 @@
+package severe
+
+import (
+	"fmt"
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+	"time"
+)
+
+// ◂     March 2015     ▸
+// Su Mo Tu We Th Fr Sa
+//  1  2  3  4  5  6  7
+//  8  9 10 11 12 13 14
+// 15 16 17 18 19 20 21
+// 22 23 24 25 26 27 28
+// 29 30 31
+//
+//        14:30
+
+const (
+	calendarW = 20
+	// a month spans at most six weeks,
+	// they are always drawn so the size stays the same
+	calendarH = 8
+)
+
+const (
+	partDate = iota
+	partHour
+	partMinute
+)
+
+// Calendar picks a date from a month grid, and the time of day
+// if ShowTime is set. The arrow keys move between the days,
+// PgUp and PgDn between the months, and Tab goes to the hour
+// and minute, which are changed with Up and Down.
+type Calendar struct {
+	Focusable
+	Nameable
+	Labelable
+
+	// Min and Max limit the dates that can be picked,
+	// a zero time has no limit
+	Min, Max time.Time
+	FirstDay time.Weekday
+	ShowTime bool
+	// MinuteStep is how much Up and Down change the minute
+	MinuteStep int
+	OnChange   func(t time.Time)
+
+	t    time.Time
+	part int
+	// zero is set until a date is picked for a zero time
+	zero bool
+}
+
+// NewCalendar starts at t, or now if t is zero. The Value of
+// a zero time stays zero until a date is picked, so that a form
+// doesn't turn a field that was left alone into the current time.
+func NewCalendar(t time.Time) *Calendar {
+	cal := &Calendar{MinuteStep: 5}
+	cal.reset(t)
+	return cal
+}
+
+func (cal *Calendar) reset(t time.Time) {
+	cal.zero = t.IsZero()
+	if cal.zero {
+		t = time.Now()
+	}
+	cal.t = t.Truncate(time.Minute)
+}
+
+func (cal *Calendar) Time() time.Time {
+	return cal.t
+}
+
+// SetTime moves to t, kept within Min and Max
+func (cal *Calendar) SetTime(t time.Time) {
+	if !cal.Min.IsZero() && t.Before(cal.Min) {
+		t = cal.Min
+	}
+	if !cal.Max.IsZero() && t.After(cal.Max) {
+		t = cal.Max
+	}
+	if t.Equal(cal.t) && !cal.zero {
+		return
+	}
+	cal.t = t
+	cal.zero = false
+	if cal.OnChange != nil {
+		cal.OnChange(t)
+	}
+}
+
+// InRange returns false for the days that are
+// entirely before Min or after Max
+func (cal *Calendar) InRange(day time.Time) bool {
+	y, m, d := day.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+	if !cal.Min.IsZero() && !end.After(cal.Min) {
+		return false
+	}
+	if !cal.Max.IsZero() && start.After(cal.Max) {
+		return false
+	}
+	return true
+}
+
+func (cal *Calendar) MoveDays(n int) {
+	cal.SetTime(cal.t.AddDate(0, 0, n))
+}
+
+// MoveMonths keeps the day of the month, or
+// the last day if the month is shorter
+func (cal *Calendar) MoveMonths(n int) {
+	y, m, d := cal.t.Date()
+	first := time.Date(y, m+time.Month(n), 1, cal.t.Hour(), cal.t.Minute(), 0, 0, cal.t.Location())
+	cal.SetTime(first.AddDate(0, 0, min(d, daysIn(first))-1))
+}
+
+// SetClock changes the time of day, keeping the date
+func (cal *Calendar) SetClock(hour, minute int) {
+	y, m, d := cal.t.Date()
+	hour = (hour%24 + 24) % 24
+	minute = (minute%60 + 60) % 60
+	cal.SetTime(time.Date(y, m, d, hour, minute, 0, 0, cal.t.Location()))
+}
+
+func daysIn(t time.Time) int {
+	y, m, _ := t.Date()
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+// HandleKey returns false for the keys it doesn't use,
+// Tab only while there is a part left to go to.
+func (cal *Calendar) HandleKey(e term.Event) bool {
+	switch e.Key {
+	case term.KeyTab:
+		if !cal.ShowTime || cal.part == partMinute {
+			cal.part = partDate
+			return false
+		}
+		cal.part++
+		return true
+	case term.KeyPgup:
+		cal.MoveMonths(-1)
+		return true
+	case term.KeyPgdn:
+		cal.MoveMonths(1)
+		return true
+	}
+
+	if cal.part == partDate {
+		switch e.Key {
+		case term.KeyArrowLeft:
+			cal.MoveDays(-1)
+		case term.KeyArrowRight:
+			cal.MoveDays(1)
+		case term.KeyArrowUp:
+			cal.MoveDays(-7)
+		case term.KeyArrowDown:
+			cal.MoveDays(7)
+		case term.KeyHome:
+			cal.MoveDays(1 - cal.t.Day())
+		case term.KeyEnd:
+			cal.MoveDays(daysIn(cal.t) - cal.t.Day())
+		default:
+			return false
+		}
+		return true
+	}
+
+	step := 1
+	hour, minute := cal.t.Hour(), cal.t.Minute()
+	if cal.part == partMinute {
+		step = max(cal.MinuteStep, 1)
+	}
+	switch e.Key {
+	case term.KeyArrowUp:
+		step = -step
+		fallthrough
+	case term.KeyArrowDown:
+		if cal.part == partHour {
+			cal.SetClock(hour+step, minute)
+		} else {
+			cal.SetClock(hour, minute+step)
+		}
+	case term.KeyArrowLeft:
+		cal.part--
+	case term.KeyArrowRight:
+		cal.part = partMinute
+	default:
+		return false
+	}
+	return true
+}
+
+// Size, Value and SetValue make the calendar usable as a FormField
+func (cal *Calendar) Size() (int, int) {
+	if cal.ShowTime {
+		return calendarW, calendarH + 2
+	}
+	return calendarW, calendarH
+}
+
+// Value is a zero time if no date was picked for one
+func (cal *Calendar) Value() interface{} {
+	if cal.zero {
+		return time.Time{}
+	}
+	return cal.t
+}
+
+func (cal *Calendar) SetValue(v interface{}) {
+	if t, ok := v.(time.Time); ok && t.IsZero() {
+		cal.reset(t)
+	} else if ok {
+		cal.SetTime(t)
+	}
+}
+
+func (cal *Calendar) Width() size.T {
+	w, _ := cal.Size()
+	return size.Const(w)
+}
+
+func (cal *Calendar) Height() size.T {
+	_, h := cal.Size()
+	return size.Const(h)
+}
+
+func (cal *Calendar) Render(canvas wind.Canvas) {
+	defer cal.drawLabel(canvas)
+	dim := uint16(term.ColorBlack | term.AttrBold)
+	selected := uint16(term.ColorBlue)
+
+	var bg uint16 = 0
+	if cal.IsFocused() {
+		bg = uint16(term.ColorRed)
+	}
+	title := []rune(cal.t.Format("January 2006"))
+	for x := 0; x < calendarW; x++ {
+		canvas.Draw(x, 0, ' ', 0, bg)
+	}
+	canvas.Draw(0, 0, '◂', 0, bg)
+	canvas.Draw(calendarW-1, 0, '▸', 0, bg)
+	for i, c := range title {
+		canvas.Draw((calendarW-len(title))/2+i, 0, c, uint16(term.AttrBold), bg)
+	}
+
+	for i := 0; i < 7; i++ {
+		day := (cal.FirstDay + time.Weekday(i)) % 7
+		drawText(canvas, i*3, 1, day.String()[:2], dim)
+	}
+
+	y, m, _ := cal.t.Date()
+	first := time.Date(y, m, 1, 0, 0, 0, 0, cal.t.Location())
+	offset := (int(first.Weekday()) - int(cal.FirstDay) + 7) % 7
+	for d := 1; d <= daysIn(first); d++ {
+		i := offset + d - 1
+		col, row := i%7, 2+i/7
+		var fg, bg uint16 = 0, 0
+		if !cal.InRange(first.AddDate(0, 0, d-1)) {
+			fg = dim
+		}
+		if d == cal.t.Day() {
+			bg = selected
+		}
+		for j, c := range fmt.Sprintf("%2d", d) {
+			canvas.Draw(col*3+j, row, c, fg, bg)
+		}
+	}
+
+	if cal.ShowTime {
+		x := (calendarW - 5) / 2
+		var hourBg, minuteBg uint16 = 0, 0
+		if cal.IsFocused() && cal.part == partHour {
+			hourBg = selected
+		}
+		if cal.IsFocused() && cal.part == partMinute {
+			minuteBg = selected
+		}
+		for i, c := range fmt.Sprintf("%02d", cal.t.Hour()) {
+			canvas.Draw(x+i, calendarH+1, c, 0, hourBg)
+		}
+		canvas.Draw(x+2, calendarH+1, ':', 0, 0)
+		for i, c := range fmt.Sprintf("%02d", cal.t.Minute()) {
+			canvas.Draw(x+3+i, calendarH+1, c, 0, minuteBg)
+		}
+	}
+}
+
+// Choose lets the user pick a date until Enter is pressed,
+// ok is false if Esc was pressed instead.
+func (cal *Calendar) Choose(flow *control.Flow) (t time.Time, ok bool) {
+	cal.part = partDate
+	runModal(flow, func(e term.Event) bool {
+		if e.Type != term.EventKey {
+			return false
+		}
+		switch e.Key {
+		case term.KeyEnter:
+			t, ok = cal.t, true
+			cal.zero = false
+			return true
+		case term.KeyEsc:
+			return true
+		}
+		cal.HandleKey(e)
+		return false
+	})
+	return t, ok
+}
+
+func (cal *Calendar) Control(flow *control.Flow) {
+	flow.TermTransfer(control.Opts{}, func(_ *control.Flow, e term.Event) {
+		cal.HandleKey(e)
+	})
+}
+
+// calendarPopup draws a Calendar in a box over an Overlay
+type calendarPopup struct {
+	cal   *Calendar
+	title string
+}
+
+func (p *calendarPopup) size() (int, int) {
+	w, h := p.cal.Size()
+	return max(w+4, len(p.title)+6), h + 2
+}
+
+func (p *calendarPopup) Width() size.T {
+	w, _ := p.size()
+	return size.Const(w)
+}
+
+func (p *calendarPopup) Height() size.T {
+	_, h := p.size()
+	return size.Const(h)
+}
+
+func (p *calendarPopup) Place(canvasW, canvasH int) (int, int, int, int) {
+	w, h := p.size()
+	return centered(canvasW, canvasH, w, h)
+}
+
+func (p *calendarPopup) Render(canvas wind.Canvas) {
+	w, h := canvas.Dimension()
+	drawBox(canvas, w, h, p.title)
+	cw, ch := p.cal.Size()
+	p.cal.Render(subCanvas(canvas, (w-cw)/2, 1, cw, ch))
+}
+
+// DateDialog asks for a date over the overlay,
+// ok is false if it was cancelled.
+func DateDialog(flow *control.Flow, overlay *Overlay, title string, cal *Calendar) (time.Time, bool) {
+	popup := &calendarPopup{cal, title}
+	overlay.Push(popup)
+	defer overlay.Remove(popup)
+	cal.Focus()
+	defer cal.Unfocus()
+	return cal.Choose(flow)
+}
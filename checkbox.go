@@ -0,0 +1,83 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+)
+
+// [x] text
+type Checkbox struct {
+	Focusable
+	Nameable
+	Labelable
+	Text     string
+	checked  bool
+	OnChange func(checked bool)
+}
+
+func NewCheckbox(text string, checked bool) *Checkbox {
+	return &Checkbox{
+		Text:    text,
+		checked: checked,
+	}
+}
+
+func (cbox *Checkbox) Width() size.T {
+	return size.Const(len([]rune(cbox.Text)) + 4)
+}
+
+func (cbox *Checkbox) Height() size.T {
+	return size.Const(1)
+}
+
+func (cbox *Checkbox) Checked() bool {
+	return cbox.checked
+}
+
+func (cbox *Checkbox) SetChecked(checked bool) {
+	if cbox.checked == checked {
+		return
+	}
+	cbox.checked = checked
+	if cbox.OnChange != nil {
+		cbox.OnChange(checked)
+	}
+}
+
+func (cbox *Checkbox) Toggle() {
+	cbox.SetChecked(!cbox.checked)
+}
+
+func (cbox *Checkbox) Render(canvas wind.Canvas) {
+	defer cbox.drawLabel(canvas)
+	fg := term.ColorDefault
+	bg := term.ColorDefault
+	if cbox.IsDisabled() {
+		fg = term.ColorBlack | term.AttrBold
+	} else if cbox.IsFocused() {
+		bg = term.ColorRed
+	}
+	mark := ' '
+	if cbox.checked {
+		mark = 'x'
+	}
+	for x, c := range []rune("[" + string(mark) + "] " + cbox.Text) {
+		canvas.Draw(x, 0, c, uint16(fg), uint16(bg))
+	}
+}
+
+func (cbox *Checkbox) DefaultKeys() control.Keymap {
+	return control.Keymap{
+		term.KeySpace: func(_ *control.Flow) { cbox.Toggle() },
+		term.KeyEnter: func(_ *control.Flow) { cbox.Toggle() },
+	}
+}
+
+func (cbox *Checkbox) Control(flow *control.Flow) {
+	if cbox.IsDisabled() {
+		return
+	}
+	flow.TermSwitch(control.Opts{}, cbox.DefaultKeys())
+}
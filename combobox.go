@@ -0,0 +1,298 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+	"strings"
+)
+
+// [ap            ▾]
+//  apple
+//  apricot
+//  grape
+
+// ComboBox is a Textfield with a drop-down list of the Items that
+// match what has been typed. Any text is accepted as the value,
+// the suggestions only help filling it in.
+//
+// The list is drawn over the layers below through Overlay, at the
+// rectangle that Geometry recorded for the combo box. Without them,
+// the combo box grows to show the list under the text.
+type ComboBox struct {
+	Focusable
+	Nameable
+	Labelable
+
+	Input *Textbox
+	Items Items
+	// Rows is the most suggestions shown at once
+	Rows int
+	// Match decides if an item is suggested for text,
+	// the default ignores case and puts prefix matches first
+	Match func(item, text string) bool
+
+	Overlay  *Overlay
+	Geometry *Geometry
+	OnChange func(text string)
+
+	w       int
+	list    *Listbox
+	matches []string
+	open    bool
+	popup   *comboPopup
+}
+
+func NewComboBox(w int, items Items) *ComboBox {
+	combo := &ComboBox{
+		Input: Textfield(max(w-1, 1)),
+		Items: items,
+		Rows:  5,
+		w:     w,
+	}
+	combo.Input.SetBuffer("")
+	combo.list = NewListbox(w, combo.Rows, ItemsFn(func() []string { return combo.matches }))
+	combo.list.AutoSize = true
+	combo.popup = &comboPopup{combo}
+	return combo
+}
+
+func (combo *ComboBox) Text() string {
+	return combo.Input.Text()
+}
+
+func (combo *ComboBox) SetText(text string) {
+	combo.Input.view.CursorHome()
+	combo.Input.SetBuffer(text)
+	for range text {
+		combo.Input.CursorRight()
+	}
+	combo.changed()
+}
+
+// Suggestions returns the items that match the text
+func (combo *ComboBox) Suggestions() []string {
+	return combo.matches
+}
+
+func (combo *ComboBox) IsOpen() bool {
+	return combo.open
+}
+
+func (combo *ComboBox) filter() {
+	text := combo.Text()
+	combo.matches = combo.matches[:0]
+	if combo.Match != nil {
+		for _, item := range combo.Items.GetItems() {
+			if combo.Match(item, text) {
+				combo.matches = append(combo.matches, item)
+			}
+		}
+	} else {
+		var rest []string
+		text = strings.ToLower(text)
+		for _, item := range combo.Items.GetItems() {
+			lower := strings.ToLower(item)
+			if strings.HasPrefix(lower, text) {
+				combo.matches = append(combo.matches, item)
+			} else if strings.Contains(lower, text) {
+				rest = append(rest, item)
+			}
+		}
+		combo.matches = append(combo.matches, rest...)
+	}
+	combo.list.SetIndex(0)
+}
+
+// Open shows the suggestions, if there are any
+func (combo *ComboBox) Open() {
+	combo.filter()
+	if len(combo.matches) == 0 {
+		combo.Close()
+		return
+	}
+	if !combo.open && combo.Overlay != nil {
+		combo.Overlay.Push(combo.popup)
+	}
+	combo.open = true
+}
+
+func (combo *ComboBox) Close() {
+	if combo.open && combo.Overlay != nil {
+		combo.Overlay.Remove(combo.popup)
+	}
+	combo.open = false
+}
+
+// Accept puts the selected suggestion in the text field
+func (combo *ComboBox) Accept() {
+	if _, item := combo.list.SelectedItem(); combo.open && item != "" {
+		combo.Close()
+		combo.SetText(item)
+	}
+}
+
+func (combo *ComboBox) changed() {
+	if combo.OnChange != nil {
+		combo.OnChange(combo.Text())
+	}
+}
+
+// HandleKey returns false for the keys it doesn't use, Up, Enter
+// and Esc are only used while the suggestions are shown.
+func (combo *ComboBox) HandleKey(e term.Event) bool {
+	switch {
+	case e.Key == term.KeyArrowDown:
+		if combo.open {
+			combo.list.SelectDown()
+		} else {
+			combo.Open()
+		}
+		return true
+	case e.Key == term.KeyArrowUp:
+		if !combo.open {
+			return false
+		}
+		if i, _ := combo.list.SelectedItem(); i <= 0 {
+			combo.Close()
+		} else {
+			combo.list.SelectUp()
+		}
+		return true
+	case e.Key == term.KeyEnter:
+		if !combo.open {
+			return false
+		}
+		combo.Accept()
+		return true
+	case e.Key == term.KeyEsc:
+		if !combo.open {
+			return false
+		}
+		combo.Close()
+		return true
+	case e.Key == term.KeyArrowLeft, e.Key == term.KeyArrowRight:
+		combo.Input.HandleKey(e)
+		return true
+	case e.Key == term.KeyBackspace, e.Key == term.KeyBackspace2, e.Key == term.KeyDelete:
+		if x, _ := combo.Input.Cursor(); x > 0 {
+			combo.Input.DeleteBack()
+			combo.edited()
+		}
+		return true
+	case e.Ch != 0, e.Key == term.KeySpace:
+		combo.Input.HandleKey(e)
+		combo.edited()
+		return true
+	}
+	combo.Close()
+	return false
+}
+
+func (combo *ComboBox) edited() {
+	combo.changed()
+	combo.Open()
+}
+
+// Size, Value and SetValue make the combo box usable as a FormField
+func (combo *ComboBox) Size() (int, int) {
+	if combo.open && combo.Overlay == nil {
+		return combo.w, 1 + combo.listRows()
+	}
+	return combo.w, 1
+}
+
+func (combo *ComboBox) Value() interface{} { return combo.Text() }
+
+func (combo *ComboBox) SetValue(v interface{}) {
+	if text, ok := v.(string); ok {
+		combo.SetText(text)
+	}
+}
+
+func (combo *ComboBox) listRows() int {
+	return min(len(combo.matches), max(combo.Rows, 1))
+}
+
+func (combo *ComboBox) Width() size.T {
+	w, _ := combo.Size()
+	return size.Const(w)
+}
+
+func (combo *ComboBox) Height() size.T {
+	_, h := combo.Size()
+	return size.Const(h)
+}
+
+func (combo *ComboBox) Render(canvas wind.Canvas) {
+	defer combo.drawLabel(canvas)
+	w, h := canvas.Dimension()
+	combo.Input.Focusable = combo.Focusable
+	combo.Input.Render(subCanvas(canvas, 0, 0, w-1, 1))
+
+	var bg uint16 = 0
+	if combo.IsFocused() {
+		bg = uint16(term.ColorRed)
+	}
+	canvas.Draw(w-1, 0, '▾', 0, bg)
+
+	if combo.open && combo.Overlay == nil {
+		combo.list.Render(subCanvas(canvas, 0, 1, w, h-1))
+	}
+}
+
+// Choose lets the user fill in the combo box until Enter is pressed
+// with the suggestions closed, ok is false if Esc was pressed instead.
+func (combo *ComboBox) Choose(flow *control.Flow) (text string, ok bool) {
+	defer combo.Close()
+	runModal(flow, func(e term.Event) bool {
+		if e.Type != term.EventKey || combo.HandleKey(e) {
+			return false
+		}
+		switch e.Key {
+		case term.KeyEnter:
+			text, ok = combo.Text(), true
+			return true
+		case term.KeyEsc:
+			return true
+		}
+		return false
+	})
+	return text, ok
+}
+
+func (combo *ComboBox) Control(flow *control.Flow) {
+	flow.TermTransfer(control.Opts{}, func(_ *control.Flow, e term.Event) {
+		combo.HandleKey(e)
+	})
+}
+
+// comboPopup is the list of suggestions drawn over an Overlay,
+// below the combo box or above it if there is no room.
+type comboPopup struct {
+	combo *ComboBox
+}
+
+func (p *comboPopup) Width() size.T  { return size.Const(p.combo.w) }
+func (p *comboPopup) Height() size.T { return size.Const(p.combo.listRows()) }
+
+func (p *comboPopup) Place(w, h int) (int, int, int, int) {
+	combo := p.combo
+	rows := combo.listRows()
+	rect := Rect{0, 0, combo.w, 1}
+	if combo.Geometry != nil {
+		if r, ok := combo.Geometry.Rect(combo); ok {
+			rect = r
+		}
+	}
+	y := rect.Y + 1
+	if y+rows > h && rect.Y-rows >= 0 {
+		y = rect.Y - rows
+	}
+	return clamp(rect.X, 0, max(w-combo.w, 0)), y, combo.w, rows
+}
+
+func (p *comboPopup) Render(canvas wind.Canvas) {
+	p.combo.list.Render(canvas)
+}
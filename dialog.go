@@ -0,0 +1,201 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+	"strings"
+)
+
+// ┌─ Title ─────────────┐
+// │                     │
+// │  Some message here  │
+// │  [input field    ]  │
+// │                     │
+// │     |OK| |Cancel|   │
+// │                     │
+// └─────────────────────┘
+
+// Dialog is a popup with a message, an optional input field and a row
+// of buttons. Run shows it over an Overlay until a button is chosen.
+type Dialog struct {
+	Title   string
+	lines   []string
+	Input   *Textbox
+	buttons []*button
+	focuser *Focuser
+}
+
+func NewDialog(title, message string, buttons ...string) *Dialog {
+	dlg := &Dialog{
+		Title: title,
+		lines: strings.Split(message, "\n"),
+	}
+	for _, text := range buttons {
+		dlg.buttons = append(dlg.buttons, Button("|"+text+"|"))
+	}
+	return dlg
+}
+
+// WithInput adds a single line input field to the dialog.
+func (dlg *Dialog) WithInput(width int, text string) *Dialog {
+	dlg.Input = Textfield(width)
+	dlg.Input.SetBuffer(text)
+	return dlg
+}
+
+func (dlg *Dialog) group() Group {
+	var row []Group
+	for _, btn := range dlg.buttons {
+		row = append(row, CompGroup(btn))
+	}
+	if dlg.Input == nil {
+		return XGroup(row...)
+	}
+	return YGroup(CompGroup(dlg.Input), XGroup(row...))
+}
+
+func (dlg *Dialog) buttonsWidth() int {
+	w := 0
+	for i, btn := range dlg.buttons {
+		if i > 0 {
+			w++
+		}
+		w += btn.width
+	}
+	return w
+}
+
+func (dlg *Dialog) Size() (int, int) {
+	w := max(len(dlg.Title)+6, dlg.buttonsWidth()+4)
+	for _, line := range dlg.lines {
+		w = max(w, len(line)+4)
+	}
+	h := 6 + len(dlg.lines)
+	if dlg.Input != nil {
+		w = max(w, dlg.Input.w+4)
+		h += 2
+	}
+	return w, h
+}
+
+func (dlg *Dialog) Width() size.T {
+	w, _ := dlg.Size()
+	return size.Const(w)
+}
+
+func (dlg *Dialog) Height() size.T {
+	_, h := dlg.Size()
+	return size.Const(h)
+}
+
+func (dlg *Dialog) Place(canvasW, canvasH int) (int, int, int, int) {
+	w, h := dlg.Size()
+	return centered(canvasW, canvasH, w, h)
+}
+
+func (dlg *Dialog) Render(canvas wind.Canvas) {
+	w, h := canvas.Dimension()
+	drawBox(canvas, w, h, dlg.Title)
+
+	y := 2
+	for _, line := range dlg.lines {
+		for x, c := range line {
+			canvas.Draw(x+2, y, c, 0, 0)
+		}
+		y++
+	}
+	if dlg.Input != nil {
+		y++
+		dlg.Input.Render(subCanvas(canvas, 2, y, w-4, 1))
+	}
+
+	x := (w - dlg.buttonsWidth()) / 2
+	for _, btn := range dlg.buttons {
+		btn.Render(subCanvas(canvas, x, h-3, btn.width, 1))
+		x += btn.width + 1
+	}
+}
+
+// Run shows the dialog until a button is chosen with Enter,
+// whose index is returned. Enter in the input field chooses
+// the first button, and Esc cancels the dialog, returning -1.
+func (dlg *Dialog) Run(flow *control.Flow, overlay *Overlay) int {
+	overlay.Push(dlg)
+	defer overlay.Remove(dlg)
+
+	dlg.focuser = NewFocuser(dlg.group())
+	dlg.focuser.Wrap = true
+	defer func() { dlg.focuser.Current().Unfocus() }()
+
+	chosen := -1
+	runModal(flow, func(e term.Event) bool {
+		if e.Type != term.EventKey {
+			return false
+		}
+		current := dlg.focuser.Current()
+		switch e.Key {
+		case term.KeyEsc:
+			return true
+		case term.KeyEnter:
+			chosen = 0
+			for i, btn := range dlg.buttons {
+				if current == btn {
+					chosen = i
+				}
+			}
+			return true
+		case term.KeyTab:
+			dlg.focuser.FocusNext()
+		case term.KeyArrowUp:
+			dlg.focuser.FocusUp()
+		case term.KeyArrowDown:
+			dlg.focuser.FocusDown()
+		default:
+			if current == dlg.Input {
+				dlg.Input.HandleKey(e)
+			} else if e.Key == term.KeyArrowLeft {
+				dlg.focuser.FocusLeft()
+			} else if e.Key == term.KeyArrowRight {
+				dlg.focuser.FocusRight()
+			}
+		}
+		return false
+	})
+	return chosen
+}
+
+// runModal passes the events to handle in a nested flow, until it
+// returns true. The screen is redrawn first, so that whatever was
+// shown for the modal appears before the first event.
+func runModal(flow *control.Flow, handle func(e term.Event) (stop bool)) {
+	go RequestRedraw()
+	flow.New(
+		control.Opts{
+			Interrupt: control.TermInterrupt(func(e term.Event, ir control.Irctrl) {
+				if handle(e) {
+					ir.Stop()
+				}
+			})},
+		func(flow *control.Flow) {
+			flow.TermTransfer(control.Opts{}, func(_ *control.Flow, _ term.Event) {})
+		})
+}
+
+// MessageBox shows a message until it's dismissed.
+func MessageBox(flow *control.Flow, overlay *Overlay, title, message string) {
+	NewDialog(title, message, "OK").Run(flow, overlay)
+}
+
+// Confirm asks a yes or no question, Esc counts as a no.
+func Confirm(flow *control.Flow, overlay *Overlay, title, message string) bool {
+	return NewDialog(title, message, "Yes", "No").Run(flow, overlay) == 0
+}
+
+// InputDialog asks for a line of text, ok is false if it was cancelled.
+func InputDialog(flow *control.Flow, overlay *Overlay, title, message, text string) (string, bool) {
+	dlg := NewDialog(title, message, "OK", "Cancel").WithInput(30, text)
+	chosen := dlg.Run(flow, overlay)
+	return dlg.Input.Text(), chosen == 0
+}
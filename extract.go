@@ -0,0 +1,90 @@
+package severe
+
+import "github.com/nvlled/wind"
+
+// Grouper is implemented by layers that know their own focus group,
+// ExtractGroup uses it instead of looking inside the layer.
+type Grouper interface {
+	FocusGroup() Group
+}
+
+// ExtractGroup builds a focus group that mirrors a composed layer tree:
+// every Component becomes a CompGroup and every Grouper gives its own group.
+//
+// The layers made by wind don't tell what they are made of, so a layout
+// with components in it is composed with the functions of the same name
+// in this package: Vlayer becomes a YGroup, Hlayer an XGroup, and the
+// decorators (Border, Size, TapRender and Wrap for the others) are
+// unwrapped. Layers without any component in them are dropped.
+//
+//	layer := Vlayer(
+//		wind.Text("title"),
+//		Border('-', '|', textbox),
+//		Hlayer(okBtn, cancelBtn),
+//	)
+//	focuser := NewFocuser(ExtractGroup(layer))
+func ExtractGroup(layer wind.Layer) Group {
+	switch layer := layer.(type) {
+	case Grouper:
+		return layer.FocusGroup()
+	case Component:
+		return CompGroup(layer)
+	}
+	return NilGroup
+}
+
+// layout is a layer made by wind, along with
+// the layers that it was made from
+type layout struct {
+	wind.Layer
+	gtype  GroupType
+	layers []wind.Layer
+}
+
+func (l *layout) FocusGroup() Group {
+	var elems []Group
+	for _, layer := range l.layers {
+		if g := ExtractGroup(layer); g != NilGroup {
+			elems = append(elems, g)
+		}
+	}
+
+	switch len(elems) {
+	case 0:
+		return NilGroup
+	case 1:
+		return elems[0]
+	}
+	if l.gtype == GtypeX {
+		return XGroup(elems...)
+	}
+	return YGroup(elems...)
+}
+
+func Vlayer(layers ...wind.Layer) wind.Layer {
+	return &layout{wind.Vlayer(layers...), GtypeY, layers}
+}
+
+func Hlayer(layers ...wind.Layer) wind.Layer {
+	return &layout{wind.Hlayer(layers...), GtypeX, layers}
+}
+
+func Border(h, v rune, layer wind.Layer) wind.Layer {
+	return Wrap(wind.Border(h, v, layer), layer)
+}
+
+func Size(w, h int, layer wind.Layer) wind.Layer {
+	return Wrap(wind.Size(w, h, layer), layer)
+}
+
+func TapRender(layer wind.Layer, fn func(wind.Layer, wind.Canvas)) wind.Layer {
+	return Wrap(wind.TapRender(layer, fn), layer)
+}
+
+// Wrap tells ExtractGroup that decorated is made from layer,
+// for the decorators that this package doesn't have:
+//
+//	Wrap(wind.SizeW(20, list).FreeHeight(), list)
+func Wrap(decorated, layer wind.Layer) wind.Layer {
+	return &layout{decorated, GtypeY, []wind.Layer{layer}}
+}
@@ -0,0 +1,238 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// /home/user/src
+// ../
+// cmd/
+// main.go
+
+// FilePicker lists a directory with a Listbox. Enter goes into
+// a directory or chooses a file, Backspace goes up, Esc cancels
+// and . toggles the hidden files.
+type FilePicker struct {
+	Focusable
+	Sizable
+	Nameable
+	Labelable
+
+	Dir string
+	// Pattern is a glob that the file names must match,
+	// directories are always listed
+	Pattern    string
+	ShowHidden bool
+	// DirsOnly lists only directories, the current one
+	// is chosen from the ./ entry
+	DirsOnly bool
+
+	lbox    *Listbox
+	entries []os.FileInfo
+	names   []string
+	err     error
+}
+
+func NewFilePicker(w, h int, dir string) *FilePicker {
+	fp := &FilePicker{
+		Sizable: Sizable{w: w, h: h},
+	}
+	fp.lbox = NewListbox(w, max(h-1, 0), ItemsFn(func() []string { return fp.names }))
+	fp.lbox.AutoSize = true
+	fp.SetDir(dir)
+	return fp
+}
+
+// SetDir lists another directory
+func (fp *FilePicker) SetDir(dir string) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	fp.Dir = dir
+	fp.Refresh()
+	fp.lbox.SetIndex(0)
+}
+
+// Refresh reads the directory again, it should be called
+// after changing Pattern, ShowHidden or DirsOnly.
+func (fp *FilePicker) Refresh() {
+	_, selected := fp.lbox.SelectedItem()
+
+	infos, err := ioutil.ReadDir(fp.Dir)
+	fp.err = err
+	fp.entries = fp.entries[:0]
+	for _, info := range infos {
+		if fp.accept(info) {
+			fp.entries = append(fp.entries, info)
+		}
+	}
+	sort.SliceStable(fp.entries, func(i, j int) bool {
+		return fp.entries[i].IsDir() && !fp.entries[j].IsDir()
+	})
+
+	fp.names = fp.names[:0]
+	if fp.DirsOnly {
+		fp.names = append(fp.names, "./")
+	}
+	if filepath.Dir(fp.Dir) != fp.Dir {
+		fp.names = append(fp.names, "../")
+	}
+	for _, info := range fp.entries {
+		fp.names = append(fp.names, entryName(info))
+	}
+	fp.selectName(selected)
+}
+
+func (fp *FilePicker) accept(info os.FileInfo) bool {
+	name := info.Name()
+	if !fp.ShowHidden && strings.HasPrefix(name, ".") {
+		return false
+	}
+	if info.IsDir() {
+		return true
+	}
+	if fp.DirsOnly {
+		return false
+	}
+	if fp.Pattern != "" {
+		matched, _ := filepath.Match(fp.Pattern, name)
+		return matched
+	}
+	return true
+}
+
+func entryName(info os.FileInfo) string {
+	if info.IsDir() {
+		return info.Name() + "/"
+	}
+	return info.Name()
+}
+
+func (fp *FilePicker) selectName(name string) {
+	for i, n := range fp.names {
+		if n == name {
+			fp.lbox.SetIndex(i)
+			return
+		}
+	}
+	fp.lbox.SetIndex(0)
+}
+
+// Selected returns the full path of the selected entry
+func (fp *FilePicker) Selected() string {
+	_, name := fp.lbox.SelectedItem()
+	if name == "" {
+		return ""
+	}
+	return filepath.Join(fp.Dir, name)
+}
+
+// Up goes to the parent directory, with the one
+// that was left selected
+func (fp *FilePicker) Up() {
+	parent := filepath.Dir(fp.Dir)
+	if parent == fp.Dir {
+		return
+	}
+	name := filepath.Base(fp.Dir) + "/"
+	fp.SetDir(parent)
+	fp.selectName(name)
+}
+
+// Enter goes into the selected directory. The path is returned
+// and ok is true when a file, or ./ in DirsOnly, is selected instead.
+func (fp *FilePicker) Enter() (path string, ok bool) {
+	_, name := fp.lbox.SelectedItem()
+	switch {
+	case name == "":
+		return "", false
+	case name == "./":
+		return fp.Dir, true
+	case name == "../":
+		fp.Up()
+		return "", false
+	case strings.HasSuffix(name, "/"):
+		fp.SetDir(filepath.Join(fp.Dir, name))
+		return "", false
+	}
+	return filepath.Join(fp.Dir, name), true
+}
+
+func (fp *FilePicker) ToggleHidden() {
+	fp.ShowHidden = !fp.ShowHidden
+	fp.Refresh()
+}
+
+func (fp *FilePicker) Render(canvas wind.Canvas) {
+	defer fp.drawLabel(canvas)
+	fp.SetSize(canvas.Dimension())
+	w, h := canvas.Dimension()
+
+	header := []rune(fp.Dir)
+	if fp.err != nil {
+		header = []rune(fp.err.Error())
+	}
+	if len(header) > w && w > 1 {
+		header = append([]rune{'…'}, header[len(header)-w+1:]...)
+	}
+	fg := term.AttrBold
+	if fp.IsFocused() {
+		fg |= term.AttrUnderline
+	}
+	x := 0
+	for _, c := range header {
+		canvas.Draw(x, 0, c, uint16(fg), 0)
+		x++
+	}
+	for ; x < w; x++ {
+		canvas.Draw(x, 0, ' ', 0, 0)
+	}
+
+	fp.lbox.Focusable = fp.Focusable
+	fp.lbox.Render(subCanvas(canvas, 0, 1, w, h-1))
+}
+
+// Choose lets the user pick a file, ok is false if Esc was pressed
+func (fp *FilePicker) Choose(flow *control.Flow) (path string, ok bool) {
+	runModal(flow, func(e term.Event) bool {
+		if e.Type != term.EventKey {
+			return false
+		}
+		switch {
+		case e.Key == term.KeyEsc:
+			return true
+		case e.Key == term.KeyEnter:
+			path, ok = fp.Enter()
+			return ok
+		case e.Key == term.KeyBackspace, e.Key == term.KeyBackspace2, e.Key == term.KeyArrowLeft:
+			fp.Up()
+		case e.Key == term.KeyArrowRight:
+			if _, name := fp.lbox.SelectedItem(); strings.HasSuffix(name, "/") && name != "./" && name != "../" {
+				fp.Enter()
+			}
+		case e.Key == term.KeyArrowUp:
+			fp.lbox.SelectUp()
+		case e.Key == term.KeyArrowDown:
+			fp.lbox.SelectDown()
+		case e.Key == term.KeyHome:
+			fp.lbox.SetIndex(0)
+		case e.Key == term.KeyEnd:
+			fp.lbox.SetIndex(len(fp.names) - 1)
+		case e.Ch == '.':
+			fp.ToggleHidden()
+		}
+		return false
+	})
+	return path, ok
+}
+
+func (fp *FilePicker) Control(flow *control.Flow) {
+	fp.Choose(flow)
+}
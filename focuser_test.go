@@ -2,7 +2,13 @@ package severe
 
 import (
 	"fmt"
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 type testComp struct {
@@ -109,3 +115,1340 @@ func TestFocusing(t *testing.T) {
 	}
 
 }
+
+func TestExtractGroup(t *testing.T) {
+	a := &testComp{name: "a"}
+	b := &testComp{name: "b"}
+	c := &testComp{name: "c"}
+	d := &testComp{name: "d"}
+
+	layer := Vlayer(
+		wind.Text("title"),
+		Border('-', '|', a),
+		Hlayer(
+			b,
+			wind.LineV(' '),
+			Size(10, 1, c),
+		),
+		wind.LineH('-'),
+		TapRender(d, func(layer wind.Layer, canvas wind.Canvas) {
+			layer.Render(canvas)
+		}),
+	)
+
+	focuser := NewFocuser(ExtractGroup(layer))
+	expect := func(name string) {
+		comp := focuser.Current().(*testComp)
+		if comp.name != name {
+			t.Errorf("expected: %v, got %v", name, comp.name)
+		}
+	}
+
+	expect("a")
+	focuser.FocusDown()
+	expect("b")
+	focuser.FocusRight()
+	expect("c")
+	focuser.FocusDown()
+	expect("d")
+	focuser.FocusUp()
+	expect("c")
+	focuser.FocusUp()
+	expect("a")
+
+	if g := ExtractGroup(wind.Text("nothing")); g != NilGroup {
+		t.Errorf("expected NilGroup, got %v", g)
+	}
+
+	if g := ExtractGroup(Hlayer(a, b)); g.Gtype() != GtypeX {
+		t.Errorf("Hlayer should extract to an XGroup")
+	}
+	if g := ExtractGroup(Vlayer(a, b)); g.Gtype() != GtypeY {
+		t.Errorf("Vlayer should extract to a YGroup")
+	}
+	if g := ExtractGroup(Wrap(wind.SizeW(10, a).FreeHeight(), a)); g.(ComponentGroup).Component() != a {
+		t.Errorf("Wrap should extract to the group of a")
+	}
+	// the layers of wind aren't looked into
+	if g := ExtractGroup(wind.Vlayer(a, b)); g != NilGroup {
+		t.Errorf("expected NilGroup for a wind.Vlayer, got %v", g)
+	}
+
+	// popups, like an open dialog, aren't focus stops
+	overlay := NewOverlay(Vlayer(a, b))
+	overlay.Push(NewDialog("title", "message", "OK"))
+	focuser = NewFocuser(ExtractGroup(overlay))
+	expect("a")
+	focuser.FocusDown()
+	expect("b")
+	focuser.FocusDown()
+	expect("b")
+}
+
+func TestFocusNextPrev(t *testing.T) {
+	a := &testComp{name: "a"}
+	b := &testComp{name: "b"}
+	c := &testComp{name: "c"}
+	d := &testComp{name: "d"}
+	e := &testComp{name: "e"}
+
+	cg := CompGroup
+	group := YGroup(
+		cg(a),
+		XGroup(
+			YGroup(cg(b), cg(c)),
+			YGroup(),
+			cg(d),
+		),
+		cg(e),
+	)
+	focuser := NewFocuser(group)
+
+	type entry struct {
+		dir      string
+		expected string
+	}
+
+	run := func(tests []entry) {
+		for _, e := range tests {
+			switch e.dir {
+			case "next":
+				focuser.FocusNext()
+			case "prev":
+				focuser.FocusPrev()
+			case "down":
+				focuser.FocusDown()
+			case "up":
+				focuser.FocusUp()
+			}
+			comp := focuser.Current().(*testComp)
+			if comp.name != e.expected {
+				t.Errorf("%s: expected: %v, got %v", e.dir, e.expected, comp.name)
+			}
+		}
+	}
+
+	run([]entry{
+		entry{"next", "b"},
+		entry{"next", "c"},
+		entry{"next", "d"},
+		entry{"next", "e"},
+		entry{"next", "e"},
+		entry{"prev", "d"},
+		entry{"prev", "c"},
+		entry{"up", "b"},
+		entry{"prev", "a"},
+		entry{"prev", "a"},
+		// lastFocus still points to b
+		entry{"down", "b"},
+	})
+
+	focuser.Wrap = true
+	run([]entry{
+		entry{"prev", "a"},
+		entry{"prev", "e"},
+		entry{"next", "a"},
+	})
+}
+
+type testCanvas struct {
+	wind.Canvas
+	parent wind.Canvas
+	x, y   int
+	w, h   int
+}
+
+func (c *testCanvas) Draw(x, y int, ch rune, fg, bg uint16) {
+	if c.parent != nil {
+		c.parent.Draw(c.x+x, c.y+y, ch, fg, bg)
+	}
+}
+
+func (c *testCanvas) Dimension() (int, int) { return c.w, c.h }
+
+func TestGeometryTrack(t *testing.T) {
+	geo := NewGeometry()
+	btn := Button("ok")
+	tracked := geo.Track(btn)
+	root := geo.Layer(NilComponent)
+	root.Render(&testCanvas{})
+
+	screen := &rootCanvas{&testCanvas{w: 80, h: 24}, geo}
+	tracked.Render(&testCanvas{parent: screen, x: 3, y: 5, w: 4, h: 1})
+
+	rect, ok := geo.Rect(btn)
+	if !ok {
+		t.Fatalf("button rect not recorded")
+	}
+	if rect != (Rect{3, 5, 4, 1}) {
+		t.Errorf("expected %v, got %v", Rect{3, 5, 4, 1}, rect)
+	}
+	if g := ExtractGroup(tracked); g.(ComponentGroup).Component() != btn {
+		t.Errorf("tracked layer should extract to the button")
+	}
+
+	// the pane's first draw comes from its tracked left side
+	left, right := Button("a"), Button("b")
+	split := HSplit(9, 1, geo.Track(left), geo.Track(right))
+	geo.Track(split).Render(&testCanvas{parent: screen, x: 10, y: 2, w: 9, h: 1})
+
+	for comp, expected := range map[Component]Rect{
+		split: {10, 2, 9, 1},
+		left:  {10, 2, 4, 1},
+		right: {15, 2, 4, 1},
+	} {
+		if rect, _ := geo.Rect(comp); rect != expected {
+			t.Errorf("expected %v, got %v", expected, rect)
+		}
+	}
+}
+
+func TestFocusingGeometry(t *testing.T) {
+	a := &testComp{name: "a"}
+	b := &testComp{name: "b"}
+	c := &testComp{name: "c"}
+	d := &testComp{name: "d"}
+	e := &testComp{name: "e"}
+	f := &testComp{name: "f"}
+	g := &testComp{name: "g"}
+	h := &testComp{name: "h"}
+	i := &testComp{name: "i"}
+	j := &testComp{name: "j"}
+
+	// same layout as in TestFocusing
+	geo := NewGeometry()
+	geo.rects[a] = Rect{0, 0, 39, 1}
+	geo.rects[b] = Rect{0, 1, 10, 1}
+	geo.rects[c] = Rect{10, 1, 17, 1}
+	geo.rects[d] = Rect{27, 1, 12, 1}
+	geo.rects[e] = Rect{0, 2, 18, 4}
+	geo.rects[f] = Rect{18, 2, 9, 7}
+	geo.rects[g] = Rect{27, 2, 12, 4}
+	geo.rects[h] = Rect{0, 6, 18, 3}
+	geo.rects[i] = Rect{27, 6, 12, 3}
+	geo.rects[j] = Rect{0, 9, 39, 1}
+
+	cg := CompGroup
+	group := YGroup(
+		cg(a),
+		XGroup(cg(b), cg(c), cg(d)),
+		XGroup(
+			YGroup(cg(e), cg(h)),
+			cg(f),
+			YGroup(cg(g), cg(i)),
+		),
+		cg(j),
+	)
+
+	focuser := NewFocuser(group)
+	focuser.UseGeometry(geo)
+
+	type entry struct {
+		dir      string
+		expected string
+	}
+
+	tests := []entry{
+		entry{"down", "c"},
+		entry{"down", "f"},
+		entry{"up", "c"},
+		entry{"left", "b"},
+		entry{"down", "e"},
+		entry{"right", "f"},
+		entry{"right", "g"},
+		entry{"down", "i"},
+		entry{"left", "f"},
+		entry{"left", "e"},
+		entry{"down", "h"},
+		entry{"down", "j"},
+		entry{"up", "f"},
+		entry{"down", "j"},
+		entry{"down", "j"},
+	}
+
+	for _, e := range tests {
+		switch e.dir {
+		case "up":
+			focuser.FocusUp()
+		case "down":
+			focuser.FocusDown()
+		case "left":
+			focuser.FocusLeft()
+		case "right":
+			focuser.FocusRight()
+		}
+		comp := focuser.Current().(*testComp)
+		if comp.name != e.expected {
+			t.Errorf("%s: expected: %v, got %v", e.dir, e.expected, comp.name)
+		}
+	}
+}
+
+type hookComp struct {
+	testComp
+	entered []string
+	left    []string
+}
+
+func (h *hookComp) FocusEnter(from Component) {
+	h.entered = append(h.entered, fmt.Sprint(from))
+}
+
+func (h *hookComp) FocusLeave(to Component) {
+	h.left = append(h.left, fmt.Sprint(to))
+}
+
+func TestFocusChange(t *testing.T) {
+	a := &hookComp{testComp: testComp{name: "a"}}
+	b := &testComp{name: "b"}
+	focuser := NewFocuser(XGroup(CompGroup(a), CompGroup(b)))
+
+	var changes []string
+	focuser.OnFocusChange(func(old, new Component) {
+		changes = append(changes, fmt.Sprintf("%v->%v", old, new))
+	})
+
+	focuser.FocusRight()
+	focuser.FocusRight()
+	focuser.FocusLeft()
+
+	expected := "[comp{a}->comp{b} comp{b}->comp{a}]"
+	if got := fmt.Sprint(changes); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := fmt.Sprint(a.left); got != "[comp{b}]" {
+		t.Errorf("unexpected FocusLeave calls: %v", got)
+	}
+	if got := len(a.entered); got != 2 {
+		t.Errorf("expected 2 FocusEnter calls, got %v", got)
+	}
+}
+
+func TestFirstFocusChange(t *testing.T) {
+	// focused when the focuser is made
+	a := &hookComp{testComp: testComp{name: "a"}}
+	NewFocuser(YGroup(CompGroup(a)))
+	if got := fmt.Sprint(a.entered); got != fmt.Sprintf("[%v]", NilComponent) {
+		t.Errorf("unexpected FocusEnter calls: %v", got)
+	}
+
+	// focused when added to an empty focuser
+	b := &hookComp{testComp: testComp{name: "b"}}
+	group := YGroup()
+	focuser := NewFocuser(group)
+	var changes []string
+	focuser.OnFocusChange(func(old, new Component) {
+		changes = append(changes, fmt.Sprintf("%v->%v", old, new))
+	})
+	group.Append(CompGroup(b))
+
+	if focuser.Current() != b {
+		t.Fatalf("expected b to be focused, got %v", focuser.Current())
+	}
+	if got, expected := fmt.Sprint(changes), fmt.Sprintf("[%v->comp{b}]", NilComponent); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := fmt.Sprint(b.entered); got != fmt.Sprintf("[%v]", NilComponent) {
+		t.Errorf("unexpected FocusEnter calls: %v", got)
+	}
+}
+
+type disabledComp struct {
+	testComp
+	Focusable
+}
+
+func TestFocusSkipDisabled(t *testing.T) {
+	a := &testComp{name: "a"}
+	b := &disabledComp{testComp: testComp{name: "b"}}
+	c := &testComp{name: "c"}
+	d := &disabledComp{testComp: testComp{name: "d"}}
+	e := &testComp{name: "e"}
+	b.Disable()
+	d.Disable()
+
+	cg := CompGroup
+	group := YGroup(
+		XGroup(cg(b), cg(a)),
+		XGroup(cg(d)),
+		XGroup(cg(c), cg(e)),
+	)
+	focuser := NewFocuser(group)
+
+	type entry struct {
+		dir      string
+		expected string
+	}
+
+	tests := []entry{
+		entry{"none", "a"},
+		entry{"left", "a"},
+		entry{"down", "c"},
+		entry{"up", "a"},
+		entry{"next", "c"},
+		entry{"prev", "a"},
+		entry{"prev", "a"},
+	}
+
+	for _, e := range tests {
+		switch e.dir {
+		case "up":
+			focuser.FocusUp()
+		case "down":
+			focuser.FocusDown()
+		case "left":
+			focuser.FocusLeft()
+		case "next":
+			focuser.FocusNext()
+		case "prev":
+			focuser.FocusPrev()
+		}
+		comp := focuser.Current().(*testComp)
+		if comp.name != e.expected {
+			t.Errorf("%s: expected: %v, got %v", e.dir, e.expected, comp.name)
+		}
+	}
+
+	b.Enable()
+	focuser.FocusLeft()
+	if comp := focuser.Current(); comp != b {
+		t.Errorf("expected b after enabling it, got %v", comp)
+	}
+}
+
+func TestGroupMutation(t *testing.T) {
+	a := &testComp{name: "a"}
+	b := &testComp{name: "b"}
+	c := &testComp{name: "c"}
+	d := &testComp{name: "d"}
+	e := &testComp{name: "e"}
+
+	cg := CompGroup
+	ga, gb, gc, gd, ge := cg(a), cg(b), cg(c), cg(d), cg(e)
+	row := XGroup(ga, gb)
+	tabs := XGroup()
+	root := YGroup(tabs, row)
+
+	focuser := NewFocuser(root)
+	expect := func(name string) {
+		t.Helper()
+		comp, ok := focuser.Current().(*testComp)
+		if !ok {
+			t.Errorf("expected: %v, got %v", name, focuser.Current())
+		} else if comp.name != name {
+			t.Errorf("expected: %v, got %v", name, comp.name)
+		}
+	}
+
+	expect("a")
+	row.Append(gc)
+	focuser.FocusRight()
+	focuser.FocusRight()
+	expect("c")
+	if gb.Next() != gc || gc.Prev() != gb || gc.Parent() != row {
+		t.Errorf("appended group is not linked")
+	}
+
+	// removing the current component moves to its neighbour
+	row.Remove(gc)
+	expect("b")
+	if gb.Next() != NilGroup || gc.Parent() != NilGroup {
+		t.Errorf("removed group is still linked")
+	}
+
+	row.Insert(0, gc)
+	focuser.FocusLeft()
+	expect("a")
+	focuser.FocusLeft()
+	expect("c")
+
+	row.Replace(gc, gd)
+	expect("d")
+
+	tabs.Append(ge)
+	focuser.FocusUp()
+	expect("e")
+
+	// moving a group into another removes it from the old one
+	row.Append(ge)
+	expect("e")
+	focuser.FocusLeft()
+	expect("b")
+	if len(tabs.Children()) != 0 {
+		t.Errorf("moved group is still in the old parent")
+	}
+
+	root.Remove(row)
+	if comp := focuser.Current(); comp != NilComponent {
+		t.Errorf("expected no focus, got %v", comp)
+	}
+	root.Append(XGroup(cg(c)))
+	expect("c")
+}
+
+func TestFindByName(t *testing.T) {
+	search := NewTextbox(10, 1)
+	search.SetName("search-field")
+	results := NewListbox(10, 5, ItemSlice{})
+	results.SetName("results-list")
+	other := NewListbox(10, 5, ItemSlice{})
+	ok := Button("ok")
+	ok.SetName("ok")
+
+	cg := CompGroup
+	group := YGroup(cg(search), XGroup(cg(results), cg(other)), cg(ok))
+	focuser := NewFocuser(group)
+
+	if comp := FindByName(group, "results-list"); comp != results {
+		t.Errorf("expected results-list, got %v", comp)
+	}
+	if comp := FindByName(group, "nope"); comp != NilComponent {
+		t.Errorf("expected NilComponent, got %v", comp)
+	}
+
+	lists := FindComponents(group, func(c Component) bool {
+		_, ok := c.(*Listbox)
+		return ok
+	})
+	if len(lists) != 2 || lists[0] != results || lists[1] != other {
+		t.Errorf("expected both listboxes, got %v", lists)
+	}
+
+	if comp := focuser.FocusByName("ok"); comp != ok || focuser.Current() != ok {
+		t.Errorf("expected ok button to be focused, got %v", focuser.Current())
+	}
+	ok.Disable()
+	focuser.FocusByName("search-field")
+	if comp := focuser.FocusByName("ok"); comp != NilComponent || focuser.Current() != search {
+		t.Errorf("disabled button shouldn't be focused")
+	}
+}
+
+func TestFocusScope(t *testing.T) {
+	a := &testComp{name: "a"}
+	b := &testComp{name: "b"}
+	c := &testComp{name: "c"}
+	yes := &testComp{name: "yes"}
+	no := &testComp{name: "no"}
+
+	cg := CompGroup
+	main := YGroup(cg(a), XGroup(cg(b), cg(c)))
+	dialog := XGroup(cg(yes), cg(no))
+
+	focuser := NewFocuser(main)
+	focuser.Wrap = true
+	expect := func(name string) {
+		t.Helper()
+		comp := focuser.Current().(*testComp)
+		if comp.name != name {
+			t.Errorf("expected: %v, got %v", name, comp.name)
+		}
+	}
+
+	focuser.FocusDown()
+	focuser.FocusRight()
+	expect("c")
+
+	focuser.PushScope(dialog)
+	expect("yes")
+	focuser.FocusUp()
+	expect("yes")
+	focuser.FocusRight()
+	expect("no")
+	focuser.FocusRight()
+	expect("no")
+	focuser.FocusNext()
+	expect("yes")
+	if focuser.ScopeDepth() != 1 {
+		t.Errorf("expected scope depth 1")
+	}
+
+	// a scope inside the main tree works too
+	focuser.PopScope()
+	expect("c")
+	focuser.PushScope(main.Children()[1])
+	focuser.FocusUp()
+	expect("c")
+	focuser.FocusLeft()
+	expect("b")
+
+	focuser.PopScope()
+	expect("c")
+	focuser.FocusUp()
+	expect("a")
+	if comp := focuser.PopScope(); comp != NilComponent {
+		t.Errorf("expected nothing to pop, got %v", comp)
+	}
+}
+
+func TestHints(t *testing.T) {
+	labels := hintLabels(3)
+	if fmt.Sprint(labels) != "[a s d]" {
+		t.Errorf("unexpected labels: %v", labels)
+	}
+	labels = hintLabels(len(HintChars) + 1)
+	if labels[0] != "aa" || labels[len(labels)-1] != "sa" {
+		t.Errorf("unexpected labels: %v, ..., %v", labels[0], labels[len(labels)-1])
+	}
+
+	var btns []Group
+	for i := 0; i < 30; i++ {
+		btns = append(btns, CompGroup(Button(fmt.Sprint(i))))
+	}
+	disabled := btns[1].(ComponentGroup).Component().(*button)
+	disabled.Disable()
+
+	focuser := NewFocuser(YGroup(btns...))
+	focuser.ShowHints()
+	if !focuser.ShowingHints() {
+		t.Fatalf("hints not shown")
+	}
+	if disabled.LabelText() != "" {
+		t.Errorf("disabled button shouldn't have a label")
+	}
+	// btns[0] is aa, btns[2] is as, btns[3] is ad
+	target := btns[3].(ComponentGroup).Component().(*button)
+	if target.LabelText() != "ad" {
+		t.Errorf("expected label ad, got %v", target.LabelText())
+	}
+
+	if _, done := focuser.HintInput('a'); done {
+		t.Errorf("hint input finished too early")
+	}
+	if target.LabelText() != "d" {
+		t.Errorf("expected the typed part to be removed, got %v", target.LabelText())
+	}
+	comp, done := focuser.HintInput('d')
+	if !done || comp != target || focuser.Current() != target {
+		t.Errorf("expected %v to be focused", target)
+	}
+	if focuser.ShowingHints() || target.LabelText() != "" {
+		t.Errorf("hints should be hidden")
+	}
+
+	focuser.ShowHints()
+	if comp, done := focuser.HintInput('x'); !done || comp != NilComponent {
+		t.Errorf("unknown label should cancel the hints")
+	}
+}
+
+// -------------------
+// | 7 | 8 | 9 | / |
+// | 4 | 5 | 6 |
+// | 1 | 2 |
+// | 0 | . | = | + |
+// -------------------
+func TestGridGroup(t *testing.T) {
+	comps := make(map[string]*testComp)
+	row := func(names ...string) []Group {
+		var groups []Group
+		for _, name := range names {
+			comps[name] = &testComp{name: name}
+			groups = append(groups, CompGroup(comps[name]))
+		}
+		return groups
+	}
+
+	display := &testComp{name: "display"}
+	grid := GridGroup(
+		row("7", "8", "9", "/"),
+		row("4", "5", "6"),
+		row("1", "2"),
+		row("0", ".", "=", "+"),
+	)
+	focuser := NewFocuser(YGroup(CompGroup(display), grid))
+
+	type entry struct {
+		dir      string
+		expected string
+	}
+
+	tests := []entry{
+		entry{"down", "7"},
+		entry{"right", "8"},
+		entry{"right", "9"},
+		entry{"right", "/"},
+		entry{"right", "/"},
+		entry{"down", "6"},
+		entry{"down", "2"},
+		entry{"down", "+"},
+		entry{"up", "2"},
+		entry{"up", "6"},
+		entry{"up", "/"},
+		entry{"up", "display"},
+		entry{"down", "/"},
+		entry{"left", "9"},
+		entry{"down", "6"},
+		entry{"down", "2"},
+		entry{"left", "1"},
+		entry{"down", "0"},
+		entry{"next", "."},
+		entry{"up", "2"},
+	}
+
+	for _, e := range tests {
+		switch e.dir {
+		case "up":
+			focuser.FocusUp()
+		case "down":
+			focuser.FocusDown()
+		case "left":
+			focuser.FocusLeft()
+		case "right":
+			focuser.FocusRight()
+		case "next":
+			focuser.FocusNext()
+		}
+		comp := focuser.Current().(*testComp)
+		if comp.name != e.expected {
+			t.Errorf("%s: expected: %v, got %v", e.dir, e.expected, comp.name)
+		}
+	}
+
+	grid.Append(row("C")...)
+	focuser.FocusDown()
+	focuser.FocusDown()
+	if comp := focuser.Current().(*testComp); comp.name != "C" {
+		t.Errorf("expected the appended row, got %v", comp.name)
+	}
+	grid.Remove(focuser.current)
+	if comp := focuser.Current().(*testComp); comp.name != "+" {
+		t.Errorf("expected +, got %v", comp.name)
+	}
+}
+
+func TestTabs(t *testing.T) {
+	a := &testComp{name: "a"}
+	b := &testComp{name: "b"}
+	c := &testComp{name: "c"}
+	d := &testComp{name: "d"}
+
+	tabs := NewTabs()
+	tabs.AddTab("one", Vlayer(a, b))
+	tabs.AddTab("two", c)
+	tabs.AddTab("empty", wind.Text("nothing here"))
+
+	layer := Vlayer(tabs, d)
+	focuser := NewFocuser(ExtractGroup(layer))
+	expect := func(name string) {
+		t.Helper()
+		if name == "tabs" {
+			if focuser.Current() != tabs {
+				t.Errorf("expected: tabs, got %v", focuser.Current())
+			}
+			return
+		}
+		comp, ok := focuser.Current().(*testComp)
+		if !ok || comp.name != name {
+			t.Errorf("expected: %v, got %v", name, focuser.Current())
+		}
+	}
+
+	expect("tabs")
+	focuser.FocusDown()
+	focuser.FocusDown()
+	expect("b")
+
+	// the focus moves off the hidden page
+	tabs.Select(1)
+	expect("c")
+	focuser.FocusUp()
+	expect("tabs")
+	focuser.FocusDown()
+	expect("c")
+	focuser.FocusDown()
+	expect("d")
+
+	tabs.Select(2)
+	focuser.FocusUp()
+	expect("tabs")
+	focuser.FocusDown()
+	expect("d")
+
+	// the page remembers its last focused component
+	tabs.Prev()
+	tabs.Prev()
+	focuser.FocusUp()
+	expect("tabs")
+	focuser.FocusDown()
+	expect("b")
+}
+
+func TestSplitPaneFocus(t *testing.T) {
+	a := &testComp{name: "a"}
+	b := &testComp{name: "b"}
+	c := &testComp{name: "c"}
+
+	split := HSplit(20, 4, Vlayer(a, b), c)
+	focuser := NewFocuser(ExtractGroup(Vlayer(split, wind.Text("help"))))
+	expect := func(name string) {
+		t.Helper()
+		if name == "split" {
+			if focuser.Current() != split {
+				t.Errorf("expected: split, got %v", focuser.Current())
+			}
+			return
+		}
+		comp, ok := focuser.Current().(*testComp)
+		if !ok || comp.name != name {
+			t.Errorf("expected: %v, got %v", name, focuser.Current())
+		}
+	}
+
+	expect("a")
+	focuser.FocusRight()
+	expect("split")
+	focuser.FocusRight()
+	expect("c")
+
+	// the focus moves off a collapsed side
+	split.ToggleSecond()
+	expect("split")
+	focuser.FocusRight()
+	expect("split")
+	focuser.FocusLeft()
+	expect("b")
+
+	split.ToggleFirst()
+	expect("split")
+	focuser.FocusRight()
+	expect("c")
+	split.ToggleFirst()
+	focuser.FocusLeft()
+	focuser.FocusLeft()
+	expect("b")
+}
+
+func TestSetIndex(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var rows RowSlice
+	var roots []TreeNode
+	for _, name := range names {
+		rows = append(rows, []string{name})
+		roots = append(roots, &TreeItem{Label: name})
+	}
+	lbox := NewListbox(10, 3, ItemSlice(names))
+	tbl := NewTable(10, 4, []Column{{Title: "name"}}, rows)
+	tree := NewTreeView(10, 3, roots...)
+
+	for _, w := range []struct {
+		name     string
+		setIndex func(int)
+		selected func() string
+	}{
+		{"Listbox", lbox.SetIndex, func() string {
+			_, item := lbox.SelectedItem()
+			return item
+		}},
+		{"Table", tbl.SetIndex, func() string {
+			_, row := tbl.SelectedRow()
+			return row[0]
+		}},
+		{"TreeView", tree.SetIndex, func() string {
+			return tree.Selected().Text()
+		}},
+	} {
+		for _, c := range []struct {
+			index    int
+			expected string
+		}{
+			{6, "g"},
+			{1, "b"}, // back up after scrolling down
+			{4, "e"},
+			{100, "h"},
+			{-1, "a"},
+		} {
+			w.setIndex(c.index)
+			if item := w.selected(); item != c.expected {
+				t.Errorf("%s.SetIndex(%v): expected %v, got %v", w.name, c.index, c.expected, item)
+			}
+		}
+	}
+}
+
+func TestCursorSegment(t *testing.T) {
+	tbox := NewTextbox(20, 5)
+	tbox.SetBuffer("ab")
+	seg := CursorSegment(tbox)
+
+	// the segment follows the cursor, whatever moved it
+	for _, step := range []struct {
+		name string
+		fn   func()
+	}{
+		{"InsertChar", func() { tbox.InsertChar('c') }},
+		{"CursorRight", tbox.CursorRight},
+		{"InsertNewline", tbox.InsertNewline},
+		{"CursorUp", tbox.CursorUp},
+		{"CursorDown", tbox.CursorDown},
+		{"CursorLeft", tbox.CursorLeft},
+		{"DeleteBack", tbox.DeleteBack},
+		// the view moves without telling, SetBuffer catches up
+		{"SetBuffer", func() { tbox.view.CursorHome(); tbox.SetBuffer("") }},
+	} {
+		step.fn()
+		x, y := tbox.Cursor()
+		if expected := fmt.Sprintf("%d:%d", y+1, x+1); seg.Text() != expected {
+			t.Errorf("%s: expected %v, got %v", step.name, expected, seg.Text())
+		}
+	}
+}
+
+type testLevel int
+
+func (l testLevel) String() string { return [...]string{"low", "high"}[l] }
+
+type testConfig struct {
+	Name    string `severe:"label=Your name,required,width=10"`
+	Port    uint16
+	Ratio   float32
+	Timeout time.Duration
+	Level   testLevel `severe:"enum=low|high"`
+	Fast    bool      `severe:"enum=safe|fast"`
+	Debug   bool
+	When    time.Time
+	Skip    string `severe:"-"`
+	Inner   struct{ A int }
+	List    []string
+	Ptr     *int
+	hidden  int
+}
+
+func TestParseFormTag(t *testing.T) {
+	typ := reflect.TypeOf(testConfig{})
+	for _, c := range []struct {
+		field    string
+		expected formTag
+		ok       bool
+	}{
+		{"Name", formTag{label: "Your name", required: true, width: 10}, true},
+		{"Port", formTag{label: "Port", width: 20}, true},
+		{"Level", formTag{label: "Level", enum: []string{"low", "high"}, width: 20}, true},
+		{"When", formTag{label: "When", width: 20}, true},
+		{"Skip", formTag{}, false},
+		{"Inner", formTag{}, false},
+		{"List", formTag{}, false},
+		{"Ptr", formTag{}, false},
+		{"hidden", formTag{}, false},
+	} {
+		sf, _ := typ.FieldByName(c.field)
+		tag, ok := parseFormTag(sf)
+		if ok != c.ok || ok && !reflect.DeepEqual(tag, c.expected) {
+			t.Errorf("%s: expected %+v %v, got %+v %v", c.field, c.expected, c.ok, tag, ok)
+		}
+	}
+}
+
+func TestSetFieldValue(t *testing.T) {
+	var conf testConfig
+	v := reflect.ValueOf(&conf).Elem()
+	when := time.Date(2015, 3, 14, 9, 26, 0, 0, time.UTC)
+	for _, c := range []struct {
+		field    string
+		value    interface{}
+		expected interface{}
+		err      string
+	}{
+		{"Name", " x ", " x ", ""},
+		{"Port", "8080", uint16(8080), ""},
+		{"Port", "70000", uint16(8080), "out of range"},
+		{"Port", "-1", uint16(8080), "not a positive whole number"},
+		{"Port", "", uint16(0), ""},
+		{"Ratio", "0.5", float32(0.5), ""},
+		{"Timeout", "1m30s", 90 * time.Second, ""},
+		{"Timeout", "90", 90 * time.Second, "not a duration, like 1h30m"},
+		{"Level", "1", testLevel(1), ""},
+		{"Debug", true, true, ""},
+		{"When", when, when, ""},
+	} {
+		field := v.FieldByName(c.field)
+		err := setFieldValue(field, c.value)
+		if msg := fmt.Sprint(err); c.err != "" && msg != c.err || c.err == "" && err != nil {
+			t.Errorf("%s=%q: expected error %q, got %v", c.field, c.value, c.err, err)
+		}
+		if got := field.Interface(); got != c.expected {
+			t.Errorf("%s=%q: expected %v, got %v", c.field, c.value, c.expected, got)
+		}
+	}
+}
+
+func TestStoreValues(t *testing.T) {
+	n := 1
+	conf := testConfig{
+		Port:    80,
+		Ratio:   0.25,
+		Timeout: time.Minute,
+		Level:   1,
+		Ptr:     &n,
+	}
+	form, err := StructForm(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the text is what parses back, not what String returns,
+	// and the enums of other kinds than string select by index
+	for name, expected := range map[string]interface{}{
+		"Port":    "80",
+		"Ratio":   "0.25",
+		"Timeout": "1m0s",
+		"Level":   "high",
+		"Fast":    "safe",
+		"Debug":   false,
+	} {
+		if got := form.Field(name).Value(); got != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, got)
+		}
+	}
+	for _, name := range []string{"Skip", "Inner", "List", "Ptr", "hidden"} {
+		if form.Field(name) != nil {
+			t.Errorf("%s: expected no field", name)
+		}
+	}
+
+	form.Field("Name").SetValue("x")
+	if !form.Check() {
+		t.Errorf("expected the form to pass its validators")
+	}
+
+	values := form.Values()
+	values["Timeout"] = "2h"
+	values["Level"] = "low"
+	values["Fast"] = "fast"
+	values["Inner"] = "ignored"
+	if err := StoreValues(&conf, values); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Name != "x" || conf.Port != 80 || conf.Ratio != 0.25 || conf.Timeout != 2*time.Hour ||
+		conf.Level != 0 || !conf.Fast || conf.Ptr != &n {
+		t.Errorf("unexpected values stored: %+v", conf)
+	}
+
+	values["Port"] = "port"
+	if err := StoreValues(&conf, values); fmt.Sprint(err) != "Port: not a positive whole number" {
+		t.Errorf("expected an error for Port, got %v", err)
+	}
+	if err := StoreValues(conf, values); err == nil {
+		t.Error("expected an error for a non-pointer")
+	}
+
+	var ratio struct {
+		Ratio float64 `severe:"enum=half|full"`
+	}
+	if _, err := StructForm(&ratio); fmt.Sprint(err) != "Ratio: an enum needs a string, integer or bool field, not float64" {
+		t.Errorf("expected an error for a float enum, got %v", err)
+	}
+	var flag struct {
+		Flag bool `severe:"enum=no|yes|maybe"`
+	}
+	if _, err := StructForm(&flag); fmt.Sprint(err) != "Flag: the enum of a bool needs two items, for false and true" {
+		t.Errorf("expected an error for a bool enum, got %v", err)
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	for _, c := range []struct {
+		pattern, text string
+		score         int
+		positions     []int
+		ok            bool
+	}{
+		{"opf", "Open file", 13, []int{0, 1, 5}, true},
+		{"of", "Open file", 8, []int{0, 5}, true},
+		{"o f", "Open file", 8, []int{0, 5}, true},
+		{"file", "Open file", 19, []int{5, 6, 7, 8}, true},
+		{"OPEN", "open file", 19, []int{0, 1, 2, 3}, true},
+		{"f", "save_file", 4, []int{5}, true},
+		{"sv", "save_file", 5, []int{0, 2}, true},
+		{"", "Open file", 0, nil, true},
+		{"fo", "Open file", 0, nil, false},
+		{"x", "Open file", 0, nil, false},
+	} {
+		score, positions, ok := fuzzyMatch(c.pattern, c.text)
+		if score != c.score || ok != c.ok || !reflect.DeepEqual(positions, c.positions) {
+			t.Errorf("%q in %q: expected %v %v %v, got %v %v %v",
+				c.pattern, c.text, c.score, c.positions, c.ok, score, positions, ok)
+		}
+	}
+}
+
+func TestPaletteRecent(t *testing.T) {
+	p := NewCommandPalette(nil)
+	var cmds []*Command
+	for _, name := range []string{"Alpha", "Beta", "Gamma"} {
+		cmds = append(cmds, p.Register(name, nil))
+	}
+	// as Run does after Gamma and then Beta were chosen
+	for _, cmd := range []*Command{cmds[2], cmds[1]} {
+		p.uses++
+		p.lastUse[cmd] = p.uses
+	}
+
+	for _, c := range []struct {
+		query    string
+		expected string
+	}{
+		{"", "Beta Gamma Alpha"},
+		// the bonus outweighs the better match of Alpha
+		{"a", "Beta Gamma Alpha"},
+		{"al", "Alpha"},
+	} {
+		p.input.SetValue(c.query)
+		p.filter()
+		var names []string
+		for _, m := range p.matches {
+			names = append(names, m.cmd.Name)
+		}
+		if got := strings.Join(names, " "); got != c.expected {
+			t.Errorf("%q: expected %v, got %v", c.query, c.expected, got)
+		}
+		if p.Selected() != p.matches[0].cmd {
+			t.Errorf("%q: expected the first match to be selected", c.query)
+		}
+	}
+
+	// Gamma keeps a bonus for ten runs
+	p.input.SetValue("")
+	for _, c := range []struct {
+		runs     int
+		expected string
+	}{
+		{8, "Beta Gamma Alpha"},
+		{1, "Beta Alpha Gamma"},
+	} {
+		for i := 0; i < c.runs; i++ {
+			p.uses++
+			p.lastUse[cmds[1]] = p.uses
+		}
+		p.filter()
+		var names []string
+		for _, m := range p.matches {
+			names = append(names, m.cmd.Name)
+		}
+		if got := strings.Join(names, " "); got != c.expected {
+			t.Errorf("%d runs after Gamma: expected %v, got %v", p.uses-p.lastUse[cmds[2]], c.expected, got)
+		}
+	}
+}
+
+func TestPaletteRenderShort(t *testing.T) {
+	p := NewCommandPalette(nil)
+	p.Register("Open file", nil)
+	p.Register("Save file", nil)
+	p.filter()
+	for h := 1; h <= 5; h++ {
+		canvas := newTextCanvas(30, h)
+		p.Render(canvas)
+		if h == 5 && !strings.HasPrefix(string(canvas.rows[3]), "│ Open file") {
+			t.Errorf("expected the first command on the fourth row, got %q", string(canvas.rows[3]))
+		}
+	}
+	p.input.SetValue("xyz")
+	p.filter()
+	canvas := newTextCanvas(30, 5)
+	p.Render(canvas)
+	if !strings.HasPrefix(string(canvas.rows[3]), "│ no matching commands") {
+		t.Errorf("expected no matches, got %q", string(canvas.rows[3]))
+	}
+}
+
+func TestCalendarZero(t *testing.T) {
+	var conf struct{ When time.Time }
+	form, err := StructForm(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal := form.Field("When").(*Calendar)
+	if cal.Time().IsZero() {
+		t.Error("expected the calendar to show the current date")
+	}
+
+	// left alone, the field stays zero
+	if err := StoreValues(&conf, form.Values()); err != nil {
+		t.Fatal(err)
+	}
+	if !conf.When.IsZero() {
+		t.Errorf("expected a zero time, got %v", conf.When)
+	}
+
+	var changed time.Time
+	cal.OnChange = func(t time.Time) { changed = t }
+	cal.MoveDays(1)
+	StoreValues(&conf, form.Values())
+	if conf.When.IsZero() || !conf.When.Equal(cal.Time()) || !changed.Equal(cal.Time()) {
+		t.Errorf("expected %v, got %v (OnChange %v)", cal.Time(), conf.When, changed)
+	}
+
+	cal.SetValue(time.Time{})
+	if v := cal.Value().(time.Time); !v.IsZero() {
+		t.Errorf("expected a zero value after SetValue, got %v", v)
+	}
+}
+
+type renderFunc func(canvas wind.Canvas)
+
+func (fn renderFunc) Width() size.T             { return size.Const(0) }
+func (fn renderFunc) Height() size.T            { return size.Const(0) }
+func (fn renderFunc) Render(canvas wind.Canvas) { fn(canvas) }
+
+// hintCanvas collects the hint letters drawn at each cell
+type hintCanvas struct {
+	testCanvas
+	hints map[Rect]string
+}
+
+func (c *hintCanvas) Draw(x, y int, ch rune, fg, bg uint16) {
+	if bg == uint16(term.ColorYellow) {
+		c.hints[Rect{x, y, 1, 1}] += string(ch)
+	}
+}
+
+// drawnComp draws something, so that its rectangle is recorded
+type drawnComp struct {
+	*testComp
+}
+
+func (c *drawnComp) Render(canvas wind.Canvas) { canvas.Draw(0, 0, 'x', 0, 0) }
+
+func TestHintLayer(t *testing.T) {
+	geo := NewGeometry()
+	btn := Button("ok")
+	comp := &drawnComp{&testComp{name: "c"}}
+	btnLayer, compLayer := geo.Track(btn), geo.Track(comp)
+
+	focuser := NewFocuser(ExtractGroup(Vlayer(btnLayer, compLayer)))
+	focuser.UseGeometry(geo)
+	layer := focuser.HintLayer(geo.Layer(renderFunc(func(canvas wind.Canvas) {
+		btnLayer.Render(&testCanvas{parent: canvas, x: 0, y: 0, w: 4, h: 1})
+		compLayer.Render(&testCanvas{parent: canvas, x: 5, y: 0, w: 4, h: 1})
+	})))
+	layer.Render(&testCanvas{w: 10, h: 1})
+	focuser.ShowHints()
+
+	// the button draws its own label, the layer the other one
+	canvas := &hintCanvas{testCanvas{w: 10, h: 1}, make(map[Rect]string)}
+	layer.Render(canvas)
+	expected := map[Rect]string{{0, 0, 1, 1}: "a", {5, 0, 1, 1}: "s"}
+	if !reflect.DeepEqual(canvas.hints, expected) {
+		t.Errorf("expected %v, got %v", expected, canvas.hints)
+	}
+
+	canvas.hints = make(map[Rect]string)
+	drawHint(canvas, 1, 0, "äö")
+	expected = map[Rect]string{{1, 0, 1, 1}: "ä", {2, 0, 1, 1}: "ö"}
+	if !reflect.DeepEqual(canvas.hints, expected) {
+		t.Errorf("expected %v, got %v", expected, canvas.hints)
+	}
+}
+
+// textCanvas keeps the characters that are drawn, row by row
+type textCanvas struct {
+	testCanvas
+	rows [][]rune
+}
+
+func newTextCanvas(w, h int) *textCanvas {
+	c := &textCanvas{testCanvas: testCanvas{w: w, h: h}}
+	for y := 0; y < h; y++ {
+		c.rows = append(c.rows, []rune(strings.Repeat(" ", w)))
+	}
+	return c
+}
+
+func (c *textCanvas) Draw(x, y int, ch rune, fg, bg uint16) {
+	if y >= 0 && y < c.h && x >= 0 && x < c.w {
+		c.rows[y][x] = ch
+	}
+}
+
+func (c *textCanvas) Width() int  { return c.w }
+func (c *textCanvas) Height() int { return c.h }
+
+func (c *textCanvas) String() string {
+	var lines []string
+	for _, row := range c.rows {
+		lines = append(lines, string(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestCheckboxChange(t *testing.T) {
+	var changes []bool
+	cbox := NewCheckbox("héllo", false)
+	cbox.OnChange = func(checked bool) { changes = append(changes, checked) }
+
+	cbox.Toggle()
+	cbox.SetChecked(true) // unchanged, no call
+	cbox.Toggle()
+	cbox.SetChecked(true)
+	if !cbox.Checked() || fmt.Sprint(changes) != "[true false true]" {
+		t.Errorf("unexpected changes: %v, checked %v", changes, cbox.Checked())
+	}
+
+	if w, _ := NewCheckField("héllo", false).Size(); w != 9 {
+		t.Errorf("expected CheckField width 9, got %v", w)
+	}
+	canvas := newTextCanvas(10, 1)
+	cbox.Render(canvas)
+	if canvas.String() != "[x] héllo " {
+		t.Errorf("unexpected render: %q", canvas.String())
+	}
+}
+
+func TestRadioGroupChange(t *testing.T) {
+	var changes []string
+	radio := NewRadioGroup("one", "töo", "three")
+	radio.OnChange = func(i int, option string) {
+		changes = append(changes, fmt.Sprintf("%d %s", i, option))
+	}
+
+	radio.SetSelected(2)
+	radio.SetSelected(2) // unchanged, no call
+	radio.SetSelected(3) // out of range
+	radio.SetSelected(-1)
+	radio.CursorUp()
+	radio.SelectCursor()
+	if i, opt := radio.Selected(); i != 1 || opt != "töo" {
+		t.Errorf("expected 1 töo, got %v %v", i, opt)
+	}
+	if fmt.Sprint(changes) != "[2 three 1 töo]" {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+
+	canvas := newTextCanvas(9, 3)
+	radio.Render(canvas)
+	if expected := "( ) one  \n(*) töo  \n( ) three"; canvas.String() != expected {
+		t.Errorf("unexpected render:\n%v", canvas)
+	}
+}
+
+func TestListboxRender(t *testing.T) {
+	lbox := NewListbox(6, 2, ItemSlice{"héllo", "", "c"})
+	canvas := newTextCanvas(6, 2)
+	lbox.Render(canvas)
+	if expected := "héllo \n      "; canvas.String() != expected {
+		t.Errorf("expected %q, got %q", expected, canvas.String())
+	}
+
+	// the view has the listbox's size before it is first rendered
+	lbox = NewListbox(6, 2, ItemSlice{"a", "b", "c"})
+	lbox.SetIndex(2)
+	canvas = newTextCanvas(6, 2)
+	lbox.Render(canvas)
+	if expected := "b     \nc     "; canvas.String() != expected {
+		t.Errorf("expected %q, got %q", expected, canvas.String())
+	}
+}
+
+func TestMenuWidths(t *testing.T) {
+	bar := NewMenuBar(nil, NewMenu("Fïle"), NewMenu("Edit"))
+	canvas := newTextCanvas(13, 1)
+	bar.Render(canvas)
+	if expected := " Fïle  Edit  "; canvas.String() != expected || bar.titleX(1) != 6 {
+		t.Errorf("expected %q with Edit at 6, got %q at %v", expected, canvas.String(), bar.titleX(1))
+	}
+
+	dd := newDropdown(NewMenu("Fïle", NewMenuItem("Öpen", term.KeyCtrlO, nil)), 0, 0)
+	if _, item := dd.lbox.SelectedItem(); dd.w != 17 || item != " Öpen  Ctrl-O  " {
+		t.Errorf("expected width 17 for %q, got %v for %q", " Öpen  Ctrl-O  ", dd.w, item)
+	}
+}
@@ -0,0 +1,416 @@
+package severe
+
+import (
+	"fmt"
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+	"strings"
+)
+
+//     Name  john
+//    Email  john@
+//           not an email address
+//    Admin  [x] can delete things
+//     Role  user
+//           admin
+//
+//           |Submit| |Cancel|
+
+// FormField is an input in a Form. HandleKey returns false for
+// the keys it doesn't use, so that the Form can move the focus.
+type FormField interface {
+	Component
+	Size() (w, h int)
+	Value() interface{}
+	SetValue(v interface{})
+	HandleKey(e term.Event) bool
+}
+
+// Validator checks the value of a field,
+// the error is shown under the field.
+type Validator func(v interface{}) error
+
+// FormValidator checks all the values of a Form. Returning a
+// *FieldError shows the message under one of the fields.
+type FormValidator func(values map[string]interface{}) error
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string { return e.Message }
+
+// Required rejects empty text and unchecked checkboxes
+func Required(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("required")
+		}
+	case bool:
+		if !v {
+			return fmt.Errorf("required")
+		}
+	case nil:
+		return fmt.Errorf("required")
+	}
+	return nil
+}
+
+// TextInput is a single line Textbox, its value is a string
+type TextInput struct {
+	*Textbox
+}
+
+func NewTextInput(w int, text string) *TextInput {
+	tbox := Textfield(w)
+	tbox.SetBuffer(text)
+	return &TextInput{tbox}
+}
+
+func (f *TextInput) Value() interface{} { return f.Text() }
+
+func (f *TextInput) SetValue(v interface{}) {
+	f.SetBuffer(fmt.Sprint(v))
+}
+
+func (f *TextInput) HandleKey(e term.Event) bool {
+	switch {
+	case e.Key == term.KeyBackspace, e.Key == term.KeyBackspace2, e.Key == term.KeyDelete:
+		// DeleteBack at the start would join the lines
+		if x, _ := f.Cursor(); x > 0 {
+			f.DeleteBack()
+		}
+		return true
+	case e.Ch != 0, e.Key == term.KeySpace,
+		e.Key == term.KeyArrowLeft, e.Key == term.KeyArrowRight:
+		f.Textbox.HandleKey(e)
+		return true
+	}
+	return false
+}
+
+// CheckField is a Checkbox toggled with Space, its value is a bool
+type CheckField struct {
+	*Checkbox
+}
+
+func NewCheckField(text string, checked bool) *CheckField {
+	return &CheckField{NewCheckbox(text, checked)}
+}
+
+func (f *CheckField) Size() (int, int)   { return len([]rune(f.Text)) + 4, 1 }
+func (f *CheckField) Value() interface{} { return f.Checked() }
+
+func (f *CheckField) SetValue(v interface{}) {
+	if checked, ok := v.(bool); ok {
+		f.SetChecked(checked)
+	}
+}
+
+func (f *CheckField) HandleKey(e term.Event) bool {
+	if e.Key == term.KeySpace {
+		f.Toggle()
+		return true
+	}
+	return false
+}
+
+// SelectField picks one of the items with a Listbox, its value
+// is the selected item. The arrow keys leave the field at either end.
+type SelectField struct {
+	*Listbox
+}
+
+func NewSelectField(w, h int, items Items) *SelectField {
+	return &SelectField{NewListbox(w, h, items)}
+}
+
+func (f *SelectField) Value() interface{} {
+	_, item := f.SelectedItem()
+	return item
+}
+
+func (f *SelectField) SetValue(v interface{}) {
+	for i, item := range f.Items.GetItems() {
+		if item == fmt.Sprint(v) {
+			f.SetIndex(i)
+			return
+		}
+	}
+}
+
+func (f *SelectField) HandleKey(e term.Event) bool {
+	i, _ := f.SelectedItem()
+	switch e.Key {
+	case term.KeyArrowUp:
+		if i > 0 {
+			f.SelectUp()
+			return true
+		}
+	case term.KeyArrowDown:
+		if i < len(f.Items.GetItems())-1 {
+			f.SelectDown()
+			return true
+		}
+	}
+	return false
+}
+
+type formRow struct {
+	name       string
+	label      string
+	field      FormField
+	validators []Validator
+	err        string
+}
+
+// Form lays out labeled fields above a Submit and a Cancel button.
+// Run lets the user fill it in, Tab and the arrow keys move between
+// the fields, and the values are returned once they are valid.
+type Form struct {
+	rows       []*formRow
+	validators []FormValidator
+	err        string
+	submit     *button
+	cancel     *button
+	group      Group
+}
+
+func NewForm() *Form {
+	return &Form{
+		submit: Button("|Submit|"),
+		cancel: Button("|Cancel|"),
+	}
+}
+
+// Add puts a field at the end of the form, name is its key in the values
+func (form *Form) Add(name, label string, field FormField, validators ...Validator) {
+	form.rows = append(form.rows, &formRow{
+		name:       name,
+		label:      label,
+		field:      field,
+		validators: validators,
+	})
+	form.group = nil
+}
+
+// Validate adds a validator that is run on all the values,
+// after every field has passed its own validators.
+func (form *Form) Validate(fn FormValidator) {
+	form.validators = append(form.validators, fn)
+}
+
+// Field returns the field with the given name, or nil
+func (form *Form) Field(name string) FormField {
+	if row := form.row(name); row != nil {
+		return row.field
+	}
+	return nil
+}
+
+func (form *Form) row(name string) *formRow {
+	for _, row := range form.rows {
+		if row.name == name {
+			return row
+		}
+	}
+	return nil
+}
+
+func (form *Form) Values() map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, row := range form.rows {
+		values[row.name] = row.field.Value()
+	}
+	return values
+}
+
+// Check runs all the validators and shows their errors,
+// it returns true if there are none.
+func (form *Form) Check() bool {
+	valid := true
+	form.err = ""
+	for _, row := range form.rows {
+		if !form.checkRow(row) {
+			valid = false
+		}
+	}
+	if !valid {
+		return false
+	}
+
+	values := form.Values()
+	for _, fn := range form.validators {
+		err := fn(values)
+		if err == nil {
+			continue
+		}
+		if ferr, ok := err.(*FieldError); ok && form.row(ferr.Field) != nil {
+			form.row(ferr.Field).err = ferr.Message
+		} else {
+			form.err = err.Error()
+		}
+		return false
+	}
+	return true
+}
+
+func (form *Form) checkRow(row *formRow) bool {
+	row.err = ""
+	v := row.field.Value()
+	for _, fn := range row.validators {
+		if err := fn(v); err != nil {
+			row.err = err.Error()
+			return false
+		}
+	}
+	return true
+}
+
+// FocusGroup has the fields from top to bottom, then the buttons
+func (form *Form) FocusGroup() Group {
+	if form.group == nil {
+		var elems []Group
+		for _, row := range form.rows {
+			elems = append(elems, CompGroup(row.field))
+		}
+		elems = append(elems, XGroup(CompGroup(form.submit), CompGroup(form.cancel)))
+		form.group = YGroup(elems...)
+	}
+	return form.group
+}
+
+func (form *Form) labelWidth() int {
+	w := 0
+	for _, row := range form.rows {
+		w = max(w, len([]rune(row.label)))
+	}
+	return w
+}
+
+func (form *Form) Size() (int, int) {
+	fieldX := form.labelWidth() + 2
+	w := fieldX + form.submit.width + form.cancel.width + 1
+	h := 2
+	for _, row := range form.rows {
+		fw, fh := row.field.Size()
+		w = max(w, fieldX+max(fw, len([]rune(row.err))))
+		h += fh
+		if row.err != "" {
+			h++
+		}
+	}
+	if form.err != "" {
+		w = max(w, len([]rune(form.err)))
+		h++
+	}
+	return w, h
+}
+
+func (form *Form) Width() size.T {
+	w, _ := form.Size()
+	return size.Const(w)
+}
+
+func (form *Form) Height() size.T {
+	_, h := form.Size()
+	return size.Const(h)
+}
+
+func (form *Form) Render(canvas wind.Canvas) {
+	labelW := form.labelWidth()
+	fieldX := labelW + 2
+	errFg := uint16(term.ColorRed)
+
+	y := 0
+	for _, row := range form.rows {
+		label := []rune(row.label)
+		for i, c := range label {
+			canvas.Draw(labelW-len(label)+i, y, c, 0, 0)
+		}
+		fw, fh := row.field.Size()
+		row.field.Render(subCanvas(canvas, fieldX, y, fw, fh))
+		y += fh
+		if row.err != "" {
+			drawText(canvas, fieldX, y, row.err, errFg)
+			y++
+		}
+	}
+
+	y++
+	if form.err != "" {
+		drawText(canvas, 0, y, form.err, errFg)
+		y++
+	}
+	form.submit.Render(subCanvas(canvas, fieldX, y, form.submit.width, 1))
+	form.cancel.Render(subCanvas(canvas, fieldX+form.submit.width+1, y, form.cancel.width, 1))
+}
+
+func drawText(canvas wind.Canvas, x, y int, text string, fg uint16) {
+	for _, c := range text {
+		canvas.Draw(x, y, c, fg, 0)
+		x++
+	}
+}
+
+// Run lets the user fill in the form until it's submitted with
+// valid values, which are returned. ok is false if it was cancelled
+// with Esc or the Cancel button.
+func (form *Form) Run(flow *control.Flow) (values map[string]interface{}, ok bool) {
+	group := form.FocusGroup()
+	focuser := NewFocuser(group)
+	focuser.Wrap = true
+	defer func() {
+		focuser.Current().Unfocus()
+		// the group is kept for the next run, but not the focuser
+		focuser.unwatch(group)
+	}()
+
+	runModal(flow, func(e term.Event) bool {
+		if e.Type != term.EventKey {
+			return false
+		}
+		current := focuser.Current()
+		if field, isField := current.(FormField); isField && field.HandleKey(e) {
+			// an error goes away once it's fixed
+			for _, row := range form.rows {
+				if row.field == field && row.err != "" {
+					form.checkRow(row)
+				}
+			}
+			return false
+		}
+		switch e.Key {
+		case term.KeyEsc:
+			return true
+		case term.KeyEnter:
+			switch current {
+			case form.cancel:
+				return true
+			case form.submit:
+				if form.Check() {
+					values, ok = form.Values(), true
+					return true
+				}
+			default:
+				focuser.FocusNext()
+			}
+		case term.KeyTab:
+			focuser.FocusNext()
+		case term.KeyArrowUp:
+			focuser.FocusUp()
+		case term.KeyArrowDown:
+			focuser.FocusDown()
+		case term.KeyArrowLeft:
+			focuser.FocusLeft()
+		case term.KeyArrowRight:
+			focuser.FocusRight()
+		}
+		return false
+	})
+	return values, ok
+}
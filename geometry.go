@@ -0,0 +1,208 @@
+package severe
+
+import (
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+)
+
+type Rect struct {
+	X, Y int
+	W, H int
+}
+
+func (r Rect) right() int  { return r.X + r.W }
+func (r Rect) bottom() int { return r.Y + r.H }
+
+// Geometry records the on-screen rectangles of tracked components.
+// The root layer must be wrapped with Layer, and each component
+// that should be found with Track, before everything is rendered:
+//
+//	geo := NewGeometry()
+//	layer := geo.Layer(Vlayer(geo.Track(btn1), geo.Track(btn2)))
+//	focuser.UseGeometry(geo)
+type Geometry struct {
+	rects map[Component]Rect
+
+	// last is the screen point of the latest draw on the root canvas
+	last  point
+	drawn bool
+}
+
+type point struct{ x, y int }
+
+func NewGeometry() *Geometry {
+	return &Geometry{
+		rects: make(map[Component]Rect),
+	}
+}
+
+func (geo *Geometry) Rect(comp Component) (Rect, bool) {
+	rect, ok := geo.rects[comp]
+	return rect, ok
+}
+
+// Layer wraps the root layer, every draw that passes
+// through its canvas is in screen coordinates.
+func (geo *Geometry) Layer(layer wind.Layer) wind.Layer {
+	return &geoRoot{layer, geo}
+}
+
+// Track wraps a component so that its rectangle is recorded
+// when rendered. ExtractGroup sees through the wrapper.
+func (geo *Geometry) Track(comp Component) wind.Layer {
+	return &geoTracked{comp, geo}
+}
+
+type geoRoot struct {
+	layer wind.Layer
+	geo   *Geometry
+}
+
+func (root *geoRoot) Width() size.T  { return root.layer.Width() }
+func (root *geoRoot) Height() size.T { return root.layer.Height() }
+
+func (root *geoRoot) FocusGroup() Group { return ExtractGroup(root.layer) }
+
+func (root *geoRoot) Render(canvas wind.Canvas) {
+	root.geo.rects = make(map[Component]Rect)
+	root.layer.Render(&rootCanvas{canvas, root.geo})
+}
+
+type rootCanvas struct {
+	wind.Canvas
+	geo *Geometry
+}
+
+func (c *rootCanvas) Draw(x, y int, ch rune, fg, bg uint16) {
+	c.geo.last = point{x, y}
+	c.geo.drawn = true
+	c.Canvas.Draw(x, y, ch, fg, bg)
+}
+
+type geoTracked struct {
+	comp Component
+	geo  *Geometry
+}
+
+func (t *geoTracked) Width() size.T  { return t.comp.Width() }
+func (t *geoTracked) Height() size.T { return t.comp.Height() }
+
+func (t *geoTracked) FocusGroup() Group {
+	if grouper, ok := t.comp.(Grouper); ok {
+		return grouper.FocusGroup()
+	}
+	return CompGroup(t.comp)
+}
+
+func (t *geoTracked) Render(canvas wind.Canvas) {
+	local := &localCanvas{Canvas: canvas, geo: t.geo}
+	t.comp.Render(local)
+	if local.found {
+		w, h := canvas.Dimension()
+		t.geo.rects[t.comp] = Rect{local.origin.x, local.origin.y, w, h}
+	}
+}
+
+// the origin of a sub-canvas isn't known, so it's worked out
+// by matching a local draw with what arrives at the root canvas.
+// Tracked layers can be nested, each canvas works out its own.
+type localCanvas struct {
+	wind.Canvas
+	geo    *Geometry
+	origin point
+	found  bool
+}
+
+func (c *localCanvas) Draw(x, y int, ch rune, fg, bg uint16) {
+	if c.found {
+		c.Canvas.Draw(x, y, ch, fg, bg)
+		return
+	}
+	c.geo.drawn = false
+	c.Canvas.Draw(x, y, ch, fg, bg)
+	if c.geo.drawn {
+		c.origin = point{c.geo.last.x - x, c.geo.last.y - y}
+		c.found = true
+	}
+}
+
+type direction int
+
+const (
+	dirUp direction = iota
+	dirDown
+	dirLeft
+	dirRight
+)
+
+// searchNearest returns the closest component in the given direction,
+// ok is false when the current component hasn't been rendered yet.
+func (geo *Geometry) searchNearest(dir direction, current, top Group) (found Group, ok bool) {
+	cgroup, isComp := current.(ComponentGroup)
+	if !isComp {
+		return NilGroup, false
+	}
+	from, ok := geo.Rect(cgroup.Component())
+	if !ok {
+		return NilGroup, false
+	}
+
+	found = NilGroup
+	best, bestSkew := -1, 0
+	walkGroup(top, func(g Group) {
+		cgroup, isComp := g.(ComponentGroup)
+		if !isComp || g == current || !isFocusable(g) {
+			return
+		}
+		to, ok := geo.Rect(cgroup.Component())
+		if !ok {
+			return
+		}
+		dist, skew := distance(dir, from, to)
+		if dist < 0 {
+			return
+		}
+		if best < 0 || dist < best || (dist == best && skew < bestSkew) {
+			best, bestSkew = dist, skew
+			found = g
+		}
+	})
+	return found, true
+}
+
+// distance is negative when to isn't in the direction of from;
+// being out of line counts twice as much as being far away.
+// skew is how far apart the centers are, it breaks ties.
+func distance(dir direction, from, to Rect) (dist int, skew int) {
+	var gap, offset int
+	switch dir {
+	case dirUp, dirDown:
+		gap = from.Y - to.bottom()
+		if dir == dirDown {
+			gap = to.Y - from.bottom()
+		}
+		offset = spanGap(from.X, from.right(), to.X, to.right())
+		skew = abs((from.X*2 + from.W) - (to.X*2 + to.W))
+	case dirLeft, dirRight:
+		gap = from.X - to.right()
+		if dir == dirRight {
+			gap = to.X - from.right()
+		}
+		offset = spanGap(from.Y, from.bottom(), to.Y, to.bottom())
+		skew = abs((from.Y*2 + from.H) - (to.Y*2 + to.H))
+	}
+	if gap < 0 {
+		return -1, 0
+	}
+	return gap + offset*2, skew
+}
+
+func spanGap(start1, end1, start2, end2 int) int {
+	if end1 <= start2 {
+		return start2 - end1 + 1
+	}
+	if end2 <= start1 {
+		return start1 - end2 + 1
+	}
+	return 0
+}
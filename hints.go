@@ -0,0 +1,199 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+	"strings"
+)
+
+// HintChars are the letters used for the hint labels,
+// the ones easiest to reach come first.
+var HintChars = "asdfghjklqwertyuiopzxcvbnm"
+
+// LabeledComponent is implemented by components that can show
+// a hint label themselves. HintLayer draws the labels of the
+// other components that are tracked by the focuser's Geometry.
+type LabeledComponent interface {
+	Label(label string)
+	Unlabel()
+}
+
+type Labelable struct {
+	label string
+}
+
+func (l *Labelable) Label(label string) { l.label = label }
+func (l *Labelable) Unlabel()           { l.label = "" }
+func (l *Labelable) LabelText() string  { return l.label }
+
+func (l *Labelable) drawLabel(canvas wind.Canvas) {
+	drawHint(canvas, 0, 0, l.label)
+}
+
+func drawHint(canvas wind.Canvas, x, y int, label string) {
+	for i, c := range []rune(label) {
+		canvas.Draw(x+i, y, c, uint16(term.ColorBlack), uint16(term.ColorYellow))
+	}
+}
+
+type hint struct {
+	label string
+	group Group
+}
+
+// ShowHints gives a label to every focusable component in the
+// current scope. If a Geometry is used, only the components
+// that were rendered get one.
+func (foc *Focuser) ShowHints() {
+	foc.HideHints()
+
+	var groups []Group
+	walkGroup(foc.top, func(g Group) {
+		if g.Gtype() != GtypeComp || !isFocusable(g) {
+			return
+		}
+		if foc.geometry != nil {
+			if _, ok := foc.geometry.Rect(g.(ComponentGroup).Component()); !ok {
+				return
+			}
+		}
+		groups = append(groups, g)
+	})
+
+	labels := hintLabels(len(groups))
+	for i, g := range groups {
+		foc.hints = append(foc.hints, hint{labels[i], g})
+		if comp, ok := g.(ComponentGroup).Component().(LabeledComponent); ok {
+			comp.Label(labels[i])
+		}
+	}
+}
+
+func (foc *Focuser) HideHints() {
+	for _, h := range foc.hints {
+		if comp, ok := h.group.(ComponentGroup).Component().(LabeledComponent); ok {
+			comp.Unlabel()
+		}
+	}
+	foc.hints = nil
+	foc.hintInput = ""
+}
+
+func (foc *Focuser) ShowingHints() bool {
+	return len(foc.hints) > 0
+}
+
+// HintInput takes the next typed letter while the hints are shown.
+// Once a label has been typed in full, its component is focused and
+// returned. done is true when the hints are hidden, either because
+// a component was found or no label starts with what was typed.
+func (foc *Focuser) HintInput(ch rune) (comp Component, done bool) {
+	input := foc.hintInput + string(ch)
+	matched := false
+	for _, h := range foc.hints {
+		if h.label == input {
+			foc.HideHints()
+			return foc.setCurrent(h.group), true
+		}
+		if strings.HasPrefix(h.label, input) {
+			matched = true
+		}
+	}
+	if !matched {
+		foc.HideHints()
+		return NilComponent, true
+	}
+
+	foc.hintInput = input
+	for _, h := range foc.hints {
+		if comp, ok := h.group.(ComponentGroup).Component().(LabeledComponent); ok {
+			if strings.HasPrefix(h.label, input) {
+				comp.Label(h.label[len(input):])
+			} else {
+				comp.Unlabel()
+			}
+		}
+	}
+	return NilComponent, false
+}
+
+// ChooseHint shows the hints and reads the label from the user,
+// Esc cancels. The newly focused component is returned,
+// or NilComponent if nothing was chosen.
+func (foc *Focuser) ChooseHint(flow *control.Flow) Component {
+	foc.ShowHints()
+	chosen := Component(NilComponent)
+	runModal(flow, func(e term.Event) bool {
+		if e.Key == term.KeyEsc {
+			foc.HideHints()
+			return true
+		}
+		if e.Ch == 0 {
+			return false
+		}
+		if comp, done := foc.HintInput(e.Ch); done {
+			chosen = comp
+			return true
+		}
+		return false
+	})
+	return chosen
+}
+
+// HintLayer draws the hint labels over layer, at the rectangles
+// recorded by the focuser's Geometry. It should be the outermost layer.
+// LabeledComponents are left to draw their own.
+func (foc *Focuser) HintLayer(layer wind.Layer) wind.Layer {
+	return &hintLayer{layer, foc}
+}
+
+type hintLayer struct {
+	layer wind.Layer
+	foc   *Focuser
+}
+
+func (hl *hintLayer) Width() size.T  { return hl.layer.Width() }
+func (hl *hintLayer) Height() size.T { return hl.layer.Height() }
+
+func (hl *hintLayer) FocusGroup() Group { return ExtractGroup(hl.layer) }
+
+func (hl *hintLayer) Render(canvas wind.Canvas) {
+	hl.layer.Render(canvas)
+	foc := hl.foc
+	if foc.geometry == nil {
+		return
+	}
+	for _, h := range foc.hints {
+		comp := h.group.(ComponentGroup).Component()
+		if _, ok := comp.(LabeledComponent); ok || !strings.HasPrefix(h.label, foc.hintInput) {
+			continue
+		}
+		if rect, ok := foc.geometry.Rect(comp); ok {
+			drawHint(canvas, rect.X, rect.Y, h.label[len(foc.hintInput):])
+		}
+	}
+}
+
+// hintLabels returns n labels of the same length,
+// so that no label is a prefix of another
+func hintLabels(n int) []string {
+	chars := []rune(HintChars)
+	width := 1
+	for count := len(chars); count < n; count *= len(chars) {
+		width++
+	}
+
+	labels := make([]string, n)
+	for i := range labels {
+		label := make([]rune, width)
+		k := i
+		for j := width - 1; j >= 0; j-- {
+			label[j] = chars[k%len(chars)]
+			k /= len(chars)
+		}
+		labels[i] = string(label)
+	}
+	return labels
+}
@@ -10,6 +10,8 @@ import (
 type Less struct {
 	Sizable
 	Focusable
+	Nameable
+	Labelable
 
 	buffer [][]rune
 	view   *Viewport
@@ -38,6 +40,7 @@ func NewLess(w, h int) *Less {
 }
 
 func (less *Less) Render(canvas wind.Canvas) {
+	defer less.drawLabel(canvas)
 	less.SetSize(canvas.Dimension())
 	canvas.Clear()
 
@@ -39,6 +39,8 @@ func (fn ItemsFn) GetItems() []string { return fn() }
 type Listbox struct {
 	Focusable
 	Sizable
+	Nameable
+	Labelable
 	focused bool
 	TabSym  string
 
@@ -52,6 +54,8 @@ func NewListbox(w, h int, items Items) *Listbox {
 		Sizable: Sizable{w: w, h: h},
 		Items:   items,
 		view: &Viewport{
+			w: w,
+			h: h,
 			bounds: func(_, _ int) (int, int) {
 				// note: lbox.items and items may happen
 				// to be different if lbox.items is re-assigned
@@ -62,6 +66,7 @@ func NewListbox(w, h int, items Items) *Listbox {
 }
 
 func (lbox *Listbox) Render(canvas wind.Canvas) {
+	defer lbox.drawLabel(canvas)
 	lbox.SetSize(canvas.Dimension())
 	lbox.view.SetSize(lbox.Size())
 
@@ -81,13 +86,12 @@ func (lbox *Listbox) Render(canvas wind.Canvas) {
 		}
 
 		x := 0
-		var c rune
-
-		for x, c = range item {
+		for _, c := range item {
 			canvas.Draw(x, y, c, 0, bgColor)
+			x++
 		}
 
-		for x = x + 1; x < canvas.Width(); x++ {
+		for ; x < canvas.Width(); x++ {
 			canvas.Draw(x, y, ' ', 0, bgColor)
 		}
 	}
@@ -119,8 +123,9 @@ func (lbox *Listbox) Choose(flow *control.Flow) (int, string) {
 	return lbox.SelectedItem()
 }
 
+// SetIndex selects the i-th item, even when the list is scrolled
 func (lbox *Listbox) SetIndex(i int) {
-	lbox.view.SetCursorY(i)
+	lbox.view.SetPointY(i)
 }
 
 func (lbox *Listbox) DefaultKeys() control.Keymap {
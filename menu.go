@@ -0,0 +1,318 @@
+package severe
+
+import (
+	"fmt"
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+	"strings"
+)
+
+//  File  Edit  View
+// ┌──────────────────┐
+// │ New      Ctrl-N  │
+// │ Open...  Ctrl-O  │
+// │ Recent         ▸ │
+// │──────────────────│
+// │ Quit     Ctrl-Q  │
+// └──────────────────┘
+
+type MenuItem struct {
+	Text    string
+	Key     term.Key // accelerator, none if zero
+	Action  func(flow *control.Flow)
+	Submenu *Menu
+
+	separator bool
+}
+
+func NewMenuItem(text string, key term.Key, action func(*control.Flow)) *MenuItem {
+	return &MenuItem{Text: text, Key: key, Action: action}
+}
+
+func SubmenuItem(text string, submenu *Menu) *MenuItem {
+	return &MenuItem{Text: text, Submenu: submenu}
+}
+
+func Separator() *MenuItem {
+	return &MenuItem{separator: true}
+}
+
+type Menu struct {
+	Title string
+	Items []*MenuItem
+}
+
+func NewMenu(title string, items ...*MenuItem) *Menu {
+	return &Menu{Title: title, Items: items}
+}
+
+// MenuBar is a row of menu titles, usually at the top of the screen.
+// Its drop-down menus are drawn over the content by the Overlay,
+// at X and Y (the screen position of the bar).
+type MenuBar struct {
+	Focusable
+	Nameable
+	Labelable
+	X, Y    int
+	menus   []*Menu
+	overlay *Overlay
+	active  int
+	stack   []*dropdown
+}
+
+func NewMenuBar(overlay *Overlay, menus ...*Menu) *MenuBar {
+	return &MenuBar{
+		menus:   menus,
+		overlay: overlay,
+	}
+}
+
+func (bar *MenuBar) titleX(i int) int {
+	x := 0
+	for _, menu := range bar.menus[:i] {
+		x += len([]rune(menu.Title)) + 2
+	}
+	return x
+}
+
+func (bar *MenuBar) Width() size.T {
+	return size.Const(bar.titleX(len(bar.menus)))
+}
+
+func (bar *MenuBar) Height() size.T {
+	return size.Const(1)
+}
+
+func (bar *MenuBar) Render(canvas wind.Canvas) {
+	defer bar.drawLabel(canvas)
+	w, _ := canvas.Dimension()
+	x := 0
+	for i, menu := range bar.menus {
+		bg := term.ColorDefault
+		if i == bar.active && (bar.IsFocused() || len(bar.stack) > 0) {
+			bg = term.ColorBlue
+		}
+		for _, c := range " " + menu.Title + " " {
+			canvas.Draw(x, 0, c, 0, uint16(bg))
+			x++
+		}
+	}
+	for ; x < w; x++ {
+		canvas.Draw(x, 0, ' ', 0, 0)
+	}
+}
+
+// Control opens the active menu. Up and down choose an item,
+// left and right go to the other menus, Enter runs the item's action
+// (after all the menus are closed) and Esc closes one menu at a time.
+func (bar *MenuBar) Control(flow *control.Flow) {
+	if len(bar.menus) == 0 {
+		return
+	}
+	var chosen *MenuItem
+	bar.openMenu(bar.active)
+
+	runModal(flow, func(e term.Event) bool {
+		if e.Type != term.EventKey {
+			return false
+		}
+		item, done := bar.handleKey(e)
+		chosen = item
+		return done
+	})
+
+	bar.closeAll()
+	if chosen != nil && chosen.Action != nil {
+		chosen.Action(flow)
+	}
+}
+
+// handleKey returns the item that was chosen, if any,
+// done is true when there are no more open menus
+func (bar *MenuBar) handleKey(e term.Event) (*MenuItem, bool) {
+	top := bar.stack[len(bar.stack)-1]
+	switch e.Key {
+	case term.KeyEsc:
+		bar.closeTop()
+	case term.KeyArrowUp:
+		top.up()
+	case term.KeyArrowDown:
+		top.down()
+	case term.KeyArrowLeft:
+		if len(bar.stack) > 1 {
+			bar.closeTop()
+		} else {
+			bar.openMenu((bar.active + len(bar.menus) - 1) % len(bar.menus))
+		}
+	case term.KeyArrowRight, term.KeyEnter:
+		item := top.selected()
+		switch {
+		case item == nil:
+		case item.Submenu != nil:
+			bar.openSubmenu(item.Submenu)
+		case e.Key == term.KeyEnter:
+			return item, true
+		default:
+			bar.openMenu((bar.active + 1) % len(bar.menus))
+		}
+	}
+	return nil, len(bar.stack) == 0
+}
+
+func (bar *MenuBar) openMenu(i int) {
+	bar.closeAll()
+	bar.active = i
+	dd := newDropdown(bar.menus[i], bar.X+bar.titleX(i), bar.Y+1)
+	bar.stack = append(bar.stack, dd)
+	bar.overlay.Push(dd)
+}
+
+func (bar *MenuBar) openSubmenu(menu *Menu) {
+	parent := bar.stack[len(bar.stack)-1]
+	i, _ := parent.lbox.SelectedItem()
+	dd := newDropdown(menu, parent.x+parent.w-1, parent.y+i+1)
+	bar.stack = append(bar.stack, dd)
+	bar.overlay.Push(dd)
+}
+
+func (bar *MenuBar) closeTop() {
+	n := len(bar.stack)
+	bar.overlay.Remove(bar.stack[n-1])
+	bar.stack = bar.stack[:n-1]
+}
+
+func (bar *MenuBar) closeAll() {
+	for len(bar.stack) > 0 {
+		bar.closeTop()
+	}
+}
+
+// Keymap has the accelerators of all the menu items,
+// and F10 to open the menu bar.
+func (bar *MenuBar) Keymap() control.Keymap {
+	keymap := control.Keymap{
+		term.KeyF10: func(flow *control.Flow) { bar.Control(flow) },
+	}
+	var add func(menu *Menu)
+	add = func(menu *Menu) {
+		for _, item := range menu.Items {
+			if item.Submenu != nil {
+				add(item.Submenu)
+			} else if item.Key != 0 && item.Action != nil {
+				keymap[item.Key] = item.Action
+			}
+		}
+	}
+	for _, menu := range bar.menus {
+		add(menu)
+	}
+	return keymap
+}
+
+// dropdown is the popup of an open menu, drawn with a Listbox
+type dropdown struct {
+	menu *Menu
+	lbox *Listbox
+	x, y int
+	w, h int
+}
+
+func newDropdown(menu *Menu, x, y int) *dropdown {
+	textw, keyw := 0, 0
+	for _, item := range menu.Items {
+		textw = max(textw, len([]rune(item.Text)))
+		keyw = max(keyw, len([]rune(keyName(item.Key))))
+	}
+	innerw := textw + keyw + 5
+
+	var lines []string
+	for _, item := range menu.Items {
+		if item.separator {
+			lines = append(lines, strings.Repeat("─", innerw))
+			continue
+		}
+		arrow := " "
+		if item.Submenu != nil {
+			arrow = "▸"
+		}
+		lines = append(lines, fmt.Sprintf(" %-*s  %*s%s ", textw, item.Text, keyw, keyName(item.Key), arrow))
+	}
+
+	lbox := NewListbox(innerw, len(lines), ItemSlice(lines))
+	lbox.AutoSize = true
+	dd := &dropdown{
+		menu: menu,
+		lbox: lbox,
+		x:    x,
+		y:    y,
+		w:    innerw + 2,
+		h:    len(lines) + 2,
+	}
+	if dd.onSeparator() {
+		dd.down()
+	}
+	return dd
+}
+
+func (dd *dropdown) selected() *MenuItem {
+	i, _ := dd.lbox.SelectedItem()
+	if i < 0 || i >= len(dd.menu.Items) {
+		return nil
+	}
+	return dd.menu.Items[i]
+}
+
+func (dd *dropdown) onSeparator() bool {
+	item := dd.selected()
+	return item != nil && item.separator
+}
+
+// up and down skip the separators, and stay put
+// if there's only separators on the way
+func (dd *dropdown) up()   { dd.move(dd.lbox.SelectUp) }
+func (dd *dropdown) down() { dd.move(dd.lbox.SelectDown) }
+
+func (dd *dropdown) move(step func()) {
+	start, _ := dd.lbox.SelectedItem()
+	for {
+		prev, _ := dd.lbox.SelectedItem()
+		step()
+		i, _ := dd.lbox.SelectedItem()
+		if i == prev {
+			dd.lbox.SetIndex(start)
+			return
+		}
+		if !dd.onSeparator() {
+			return
+		}
+	}
+}
+
+func (dd *dropdown) Width() size.T  { return size.Const(dd.w) }
+func (dd *dropdown) Height() size.T { return size.Const(dd.h) }
+
+func (dd *dropdown) Place(w, h int) (int, int, int, int) {
+	x := clamp(dd.x, 0, max(w-dd.w, 0))
+	y := clamp(dd.y, 0, max(h-dd.h, 0))
+	return x, y, dd.w, dd.h
+}
+
+func (dd *dropdown) Render(canvas wind.Canvas) {
+	w, h := canvas.Dimension()
+	drawBox(canvas, w, h, "")
+	dd.lbox.Render(subCanvas(canvas, 1, 1, w-2, h-2))
+}
+
+func keyName(key term.Key) string {
+	switch {
+	case key == 0:
+		return ""
+	case key >= term.KeyCtrlA && key <= term.KeyCtrlZ:
+		return "Ctrl-" + string(rune('A'+key-term.KeyCtrlA))
+	case key <= term.KeyF1 && key >= term.KeyF12:
+		return fmt.Sprintf("F%d", term.KeyF1-key+1)
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package severe
+
+import (
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+)
+
+// Popup is a layer that is drawn over the base layer of an Overlay.
+// Place returns where it goes, given the size of the whole canvas.
+type Popup interface {
+	wind.Layer
+	Place(w, h int) (x, y, pw, ph int)
+}
+
+// Overlay draws popups (dialogs, menus, ...) on top of a base layer,
+// the last one pushed is drawn last.
+type Overlay struct {
+	base   wind.Layer
+	popups []Popup
+}
+
+// NewOverlay takes a nil base when the base needs the overlay
+// itself, like a MenuBar does. It's set later with SetBase.
+func NewOverlay(base wind.Layer) *Overlay {
+	return &Overlay{base: base}
+}
+
+func (ov *Overlay) SetBase(base wind.Layer) {
+	ov.base = base
+}
+
+func (ov *Overlay) Push(p Popup) {
+	ov.popups = append(ov.popups, p)
+}
+
+func (ov *Overlay) Remove(p Popup) {
+	for i, p_ := range ov.popups {
+		if p_ == p {
+			ov.popups = append(ov.popups[:i:i], ov.popups[i+1:]...)
+			return
+		}
+	}
+}
+
+// FocusGroup is the group of the base layer, popups are left out
+func (ov *Overlay) FocusGroup() Group { return ExtractGroup(ov.base) }
+
+func (ov *Overlay) Width() size.T {
+	if ov.base == nil {
+		return size.Const(0)
+	}
+	return ov.base.Width()
+}
+
+func (ov *Overlay) Height() size.T {
+	if ov.base == nil {
+		return size.Const(0)
+	}
+	return ov.base.Height()
+}
+
+func (ov *Overlay) Render(canvas wind.Canvas) {
+	if ov.base != nil {
+		ov.base.Render(canvas)
+	}
+	for _, p := range ov.popups {
+		w, h := canvas.Dimension()
+		x, y, pw, ph := p.Place(w, h)
+		sub := subCanvas(canvas, x, y, pw, ph)
+		sub.Clear()
+		p.Render(sub)
+	}
+}
+
+// centered returns the position of a w×h box in the middle of the canvas
+func centered(canvasW, canvasH, w, h int) (int, int, int, int) {
+	w, h = min(w, canvasW), min(h, canvasH)
+	return (canvasW - w) / 2, (canvasH - h) / 2, w, h
+}
+
+// region is a part of another canvas, draws outside of it are dropped
+type region struct {
+	wind.Canvas
+	x, y int
+	w, h int
+}
+
+func subCanvas(canvas wind.Canvas, x, y, w, h int) *region {
+	return &region{canvas, x, y, w, h}
+}
+
+func (r *region) Draw(x, y int, ch rune, fg, bg uint16) {
+	if x < 0 || y < 0 || x >= r.w || y >= r.h {
+		return
+	}
+	r.Canvas.Draw(r.x+x, r.y+y, ch, fg, bg)
+}
+
+func (r *region) Dimension() (int, int) { return r.w, r.h }
+func (r *region) Width() int            { return r.w }
+func (r *region) Height() int           { return r.h }
+
+func (r *region) Clear() {
+	for y := 0; y < r.h; y++ {
+		for x := 0; x < r.w; x++ {
+			r.Draw(x, y, ' ', 0, 0)
+		}
+	}
+}
+
+func drawBox(canvas wind.Canvas, w, h int, title string) {
+	for x := 1; x < w-1; x++ {
+		canvas.Draw(x, 0, '─', 0, 0)
+		canvas.Draw(x, h-1, '─', 0, 0)
+	}
+	for y := 1; y < h-1; y++ {
+		canvas.Draw(0, y, '│', 0, 0)
+		canvas.Draw(w-1, y, '│', 0, 0)
+	}
+	canvas.Draw(0, 0, '┌', 0, 0)
+	canvas.Draw(w-1, 0, '┐', 0, 0)
+	canvas.Draw(0, h-1, '└', 0, 0)
+	canvas.Draw(w-1, h-1, '┘', 0, 0)
+	if title != "" {
+		for i, c := range " " + title + " " {
+			if 2+i < w-2 {
+				canvas.Draw(2+i, 0, c, 0, 0)
+			}
+		}
+	}
+}
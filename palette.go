@@ -0,0 +1,302 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ┌─ Commands ─────────────────────────┐
+// │ > opfi                             │
+// │────────────────────────────────────│
+// │ Open file                   Ctrl-O │
+// │ Open folder          File          │
+// └────────────────────────────────────┘
+
+// Command is an entry of a CommandPalette. The Name is what
+// the typed text is matched against, the Description and the
+// name of the Key are shown next to it.
+type Command struct {
+	Name        string
+	Description string
+	Key         term.Key
+	Action      func(*control.Flow)
+}
+
+// CommandPalette finds a command by fuzzy matching its name
+// and runs it. The commands used recently are ranked first.
+type CommandPalette struct {
+	Title string
+	// Rows is how many commands are shown at once
+	Rows int
+
+	overlay  *Overlay
+	commands []*Command
+	lastUse  map[*Command]int
+	uses     int
+	input    *TextInput
+	matches  []paletteMatch
+	view     *Viewport
+	w        int
+}
+
+type paletteMatch struct {
+	cmd       *Command
+	score     int
+	positions []int
+}
+
+func NewCommandPalette(overlay *Overlay) *CommandPalette {
+	p := &CommandPalette{
+		Title:   "Commands",
+		Rows:    10,
+		overlay: overlay,
+		lastUse: make(map[*Command]int),
+		w:       50,
+	}
+	p.input = NewTextInput(p.w-6, "")
+	p.view = &Viewport{
+		w: 1,
+		h: p.Rows,
+		bounds: func(_, _ int) (int, int) {
+			return 0, len(p.matches)
+		},
+	}
+	return p
+}
+
+// Register adds a command, it is returned so that
+// the Description or Key can be filled in.
+func (p *CommandPalette) Register(name string, action func(*control.Flow)) *Command {
+	cmd := &Command{Name: name, Action: action}
+	p.commands = append(p.commands, cmd)
+	return cmd
+}
+
+// RegisterKeymap adds a command for every key in keymap that has
+// a name in names, such as the DefaultKeys of a component.
+// The description is shown next to the names.
+func (p *CommandPalette) RegisterKeymap(description string, keymap control.Keymap, names map[term.Key]string) {
+	var keys []term.Key
+	for key := range names {
+		if _, ok := keymap[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return names[keys[i]] < names[keys[j]] })
+	for _, key := range keys {
+		cmd := p.Register(names[key], keymap[key])
+		cmd.Description = description
+		cmd.Key = key
+	}
+}
+
+func (p *CommandPalette) Commands() []*Command {
+	return p.commands
+}
+
+// filter ranks the commands that match the input, without input
+// the recently used ones come first and the rest by name
+func (p *CommandPalette) filter() {
+	pattern := p.input.Text()
+	p.matches = p.matches[:0]
+	for _, cmd := range p.commands {
+		score, positions, ok := fuzzyMatch(pattern, cmd.Name)
+		if !ok {
+			continue
+		}
+		if last, used := p.lastUse[cmd]; used && p.uses-last < 10 {
+			// the commands used in the last ten runs get a bonus,
+			// the more recent the bigger
+			score += 10 - (p.uses - last)
+		}
+		p.matches = append(p.matches, paletteMatch{cmd, score, positions})
+	}
+	sort.SliceStable(p.matches, func(i, j int) bool {
+		a, b := p.matches[i], p.matches[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		return a.cmd.Name < b.cmd.Name
+	})
+	p.view.SetPointY(0)
+}
+
+// fuzzyMatch looks for the letters of pattern in text, in order.
+// Consecutive letters and letters that start a word score higher.
+// positions are the rune indices of the matched letters.
+func fuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	runes := []rune(text)
+	i := 0
+	prev := -2
+	for _, pc := range strings.ToLower(pattern) {
+		if unicode.IsSpace(pc) {
+			continue
+		}
+		for i < len(runes) && unicode.ToLower(runes[i]) != pc {
+			i++
+		}
+		if i >= len(runes) {
+			return 0, nil, false
+		}
+		score++
+		if i == prev+1 {
+			score += 4
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 3
+		}
+		positions = append(positions, i)
+		prev = i
+		i++
+	}
+	return score, positions, true
+}
+
+func (p *CommandPalette) Selected() *Command {
+	_, i := p.view.Point()
+	if i < 0 || i >= len(p.matches) {
+		return nil
+	}
+	return p.matches[i].cmd
+}
+
+func (p *CommandPalette) rows() int {
+	return max(p.Rows, 1)
+}
+
+func (p *CommandPalette) Width() size.T  { return size.Const(p.w) }
+func (p *CommandPalette) Height() size.T { return size.Const(p.rows() + 4) }
+
+// Place puts the palette near the top, like a drop-down
+func (p *CommandPalette) Place(w, h int) (int, int, int, int) {
+	pw, ph := min(p.w, w), min(p.rows()+4, h)
+	return (w - pw) / 2, min(2, h-ph), pw, ph
+}
+
+func (p *CommandPalette) Render(canvas wind.Canvas) {
+	w, h := canvas.Dimension()
+	drawBox(canvas, w, h, p.Title)
+
+	canvas.Draw(2, 1, '>', uint16(term.AttrBold), 0)
+	p.input.Render(subCanvas(canvas, 4, 1, w-6, 1))
+	for x := 1; x < w-1; x++ {
+		canvas.Draw(x, 2, '─', 0, 0)
+	}
+
+	// a short terminal may leave no room for the list
+	rows := max(h-4, 0)
+	p.view.SetSize(1, rows)
+	if rows == 0 {
+		return
+	}
+	_, cursY := p.view.Cursor()
+	_, offY := p.view.Offset()
+	list := subCanvas(canvas, 1, 3, w-2, rows)
+	if len(p.matches) == 0 {
+		drawText(list, 1, 0, "no matching commands", uint16(term.ColorBlack|term.AttrBold))
+		return
+	}
+	endY := min(offY+rows, len(p.matches))
+	if endY <= offY {
+		return
+	}
+	for y, m := range p.matches[offY:endY] {
+		var bg uint16 = 0
+		if y == cursY {
+			bg = uint16(term.ColorBlue)
+		}
+		p.drawMatch(list, y, m, bg)
+	}
+}
+
+func (p *CommandPalette) drawMatch(canvas wind.Canvas, y int, m paletteMatch, bg uint16) {
+	w := canvas.Width()
+	for x := 0; x < w; x++ {
+		canvas.Draw(x, y, ' ', 0, bg)
+	}
+
+	matched := make(map[int]bool)
+	for _, i := range m.positions {
+		matched[i] = true
+	}
+	x := 1
+	for i, c := range []rune(m.cmd.Name) {
+		fg := uint16(0)
+		if matched[i] {
+			fg = uint16(term.ColorYellow | term.AttrBold)
+		}
+		canvas.Draw(x, y, c, fg, bg)
+		x++
+	}
+
+	key := []rune(keyName(m.cmd.Key))
+	keyX := w - 1 - len(key)
+	for i, c := range key {
+		canvas.Draw(keyX+i, y, c, 0, bg)
+	}
+	x += 2
+	for _, c := range m.cmd.Description {
+		if x >= keyX-1 {
+			break
+		}
+		canvas.Draw(x, y, c, uint16(term.ColorBlack|term.AttrBold), bg)
+		x++
+	}
+}
+
+// Run shows the palette over the overlay until a command is chosen
+// with Enter, which is then run and returned. Esc cancels, returning nil.
+func (p *CommandPalette) Run(flow *control.Flow) *Command {
+	p.input.view.CursorHome()
+	p.input.SetBuffer("")
+	p.input.Focus()
+	p.filter()
+
+	p.overlay.Push(p)
+
+	var chosen *Command
+	runModal(flow, func(e term.Event) bool {
+		if e.Type != term.EventKey {
+			return false
+		}
+		switch e.Key {
+		case term.KeyEsc:
+			return true
+		case term.KeyEnter:
+			chosen = p.Selected()
+			return true
+		case term.KeyArrowUp:
+			p.view.CursorUp()
+		case term.KeyArrowDown:
+			p.view.CursorDown()
+		default:
+			if p.input.HandleKey(e) {
+				p.filter()
+			}
+		}
+		return false
+	})
+
+	p.overlay.Remove(p)
+	p.input.Unfocus()
+	if chosen != nil {
+		p.uses++
+		p.lastUse[chosen] = p.uses
+		if chosen.Action != nil {
+			chosen.Action(flow)
+		}
+	}
+	return chosen
+}
+
+// Keymap opens the palette with Ctrl-P
+func (p *CommandPalette) Keymap() control.Keymap {
+	return control.Keymap{
+		term.KeyCtrlP: func(flow *control.Flow) { p.Run(flow) },
+	}
+}
@@ -0,0 +1,271 @@
+package severe
+
+import (
+	"fmt"
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+	"math"
+	"sync"
+	"time"
+)
+
+// RequestRedraw is called when a widget has been updated
+// from another goroutine. The default wakes up term.PollEvent,
+// so that the control loop gets an event and EventEnded
+// can redraw the screen.
+var RequestRedraw = term.Interrupt
+
+// asyncState is embedded by widgets that can be
+// updated from goroutines other than the control loop
+type asyncState struct {
+	sync.Mutex
+	dirty bool
+}
+
+// changed must be called without holding the lock,
+// only one redraw is requested until the next render
+func (a *asyncState) changed() {
+	a.Lock()
+	request := !a.dirty
+	a.dirty = true
+	a.Unlock()
+	if request {
+		go RequestRedraw()
+	}
+}
+
+// [#########...........] 45% 1m20s
+type ProgressBar struct {
+	Sizable
+	asyncState
+	done  int
+	total int
+	label string
+	start time.Time
+}
+
+func NewProgressBar(w int, total int) *ProgressBar {
+	return &ProgressBar{
+		Sizable: Sizable{w: w, h: 1},
+		total:   total,
+		start:   time.Now(),
+	}
+}
+
+func (pb *ProgressBar) SetProgress(done int) {
+	pb.Lock()
+	pb.done = clamp(done, 0, pb.total)
+	pb.Unlock()
+	pb.changed()
+}
+
+func (pb *ProgressBar) Add(n int) {
+	pb.Lock()
+	pb.done = clamp(pb.done+n, 0, pb.total)
+	pb.Unlock()
+	pb.changed()
+}
+
+func (pb *ProgressBar) SetTotal(total int) {
+	pb.Lock()
+	pb.total = total
+	pb.done = clamp(pb.done, 0, total)
+	pb.Unlock()
+	pb.changed()
+}
+
+func (pb *ProgressBar) SetLabel(label string) {
+	pb.Lock()
+	pb.label = label
+	pb.Unlock()
+	pb.changed()
+}
+
+// Reset sets the progress back to zero,
+// the ETA is then measured from now
+func (pb *ProgressBar) Reset() {
+	pb.Lock()
+	pb.done = 0
+	pb.start = time.Now()
+	pb.Unlock()
+	pb.changed()
+}
+
+func (pb *ProgressBar) Percent() int {
+	pb.Lock()
+	defer pb.Unlock()
+	return pb.percent()
+}
+
+func (pb *ProgressBar) percent() int {
+	if pb.total <= 0 {
+		return 0
+	}
+	return pb.done * 100 / pb.total
+}
+
+// ETA is estimated from the time taken so far,
+// it's negative when it can't be told yet
+func (pb *ProgressBar) ETA() time.Duration {
+	pb.Lock()
+	defer pb.Unlock()
+	return pb.eta()
+}
+
+func (pb *ProgressBar) eta() time.Duration {
+	if pb.done <= 0 {
+		return -1
+	}
+	// in float64, with totals in bytes the product overflows a Duration
+	elapsed := time.Since(pb.start)
+	left := float64(elapsed) * float64(pb.total-pb.done) / float64(pb.done)
+	if left >= math.MaxInt64 {
+		return math.MaxInt64 / time.Second * time.Second
+	}
+	return time.Duration(left) / time.Second * time.Second
+}
+
+func (pb *ProgressBar) Done() bool {
+	pb.Lock()
+	defer pb.Unlock()
+	return pb.total > 0 && pb.done >= pb.total
+}
+
+func (pb *ProgressBar) Render(canvas wind.Canvas) {
+	pb.Lock()
+	defer pb.Unlock()
+	pb.dirty = false
+	pb.SetSize(canvas.Dimension())
+	w, _ := pb.Size()
+
+	info := fmt.Sprintf(" %3d%%", pb.percent())
+	if eta := pb.eta(); eta >= 0 && pb.done < pb.total {
+		info += " " + eta.String()
+	}
+	if pb.label != "" {
+		info = " " + pb.label + info
+	}
+
+	barw := w - len(info) - 2
+	if barw < 1 {
+		barw = w - 2
+		info = ""
+	}
+	filled := 0
+	if pb.total > 0 {
+		filled = barw * pb.done / pb.total
+	}
+
+	canvas.Draw(0, 0, '[', 0, 0)
+	for x := 0; x < barw; x++ {
+		if x < filled {
+			canvas.Draw(x+1, 0, ' ', 0, uint16(term.ColorBlue))
+		} else {
+			canvas.Draw(x+1, 0, '.', 0, 0)
+		}
+	}
+	canvas.Draw(barw+1, 0, ']', 0, 0)
+	for i, c := range info {
+		canvas.Draw(barw+2+i, 0, c, 0, 0)
+	}
+}
+
+var SpinnerFrames = []rune(`|/-\`)
+
+// Spinner shows that something is going on,
+// when it can't be told how far along it is.
+type Spinner struct {
+	asyncState
+	Frames   []rune
+	Interval time.Duration
+	label    string
+	frame    int
+	stop     chan struct{}
+}
+
+func NewSpinner(label string) *Spinner {
+	return &Spinner{
+		Frames:   SpinnerFrames,
+		Interval: 100 * time.Millisecond,
+		label:    label,
+	}
+}
+
+func (sp *Spinner) Width() size.T {
+	sp.Lock()
+	defer sp.Unlock()
+	return size.Const(len(sp.label) + 2)
+}
+
+func (sp *Spinner) Height() size.T {
+	return size.Const(1)
+}
+
+func (sp *Spinner) SetLabel(label string) {
+	sp.Lock()
+	sp.label = label
+	sp.Unlock()
+	sp.changed()
+}
+
+// Start makes the spinner spin in its own goroutine,
+// until Stop is called.
+func (sp *Spinner) Start() {
+	sp.Lock()
+	defer sp.Unlock()
+	if sp.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	sp.stop = stop
+	go func() {
+		ticker := time.NewTicker(sp.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				sp.Step()
+			}
+		}
+	}()
+}
+
+func (sp *Spinner) Stop() {
+	sp.Lock()
+	if sp.stop != nil {
+		close(sp.stop)
+		sp.stop = nil
+	}
+	sp.Unlock()
+	sp.changed()
+}
+
+func (sp *Spinner) Spinning() bool {
+	sp.Lock()
+	defer sp.Unlock()
+	return sp.stop != nil
+}
+
+// Step shows the next frame, for driving the spinner by hand.
+func (sp *Spinner) Step() {
+	sp.Lock()
+	sp.frame++
+	sp.Unlock()
+	sp.changed()
+}
+
+func (sp *Spinner) Render(canvas wind.Canvas) {
+	sp.Lock()
+	defer sp.Unlock()
+	sp.dirty = false
+
+	if len(sp.Frames) > 0 {
+		canvas.Draw(0, 0, sp.Frames[sp.frame%len(sp.Frames)], 0, 0)
+	}
+	for x, c := range sp.label {
+		canvas.Draw(x+2, 0, c, 0, 0)
+	}
+}
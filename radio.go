@@ -0,0 +1,117 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+)
+
+// (*) one
+// ( ) two
+// ( ) three
+type RadioGroup struct {
+	Focusable
+	Nameable
+	Labelable
+	options  []string
+	selected int
+	cursor   int
+	OnChange func(index int, option string)
+}
+
+func NewRadioGroup(options ...string) *RadioGroup {
+	return &RadioGroup{
+		options: options,
+	}
+}
+
+func (radio *RadioGroup) Width() size.T {
+	maxw := 0
+	for _, opt := range radio.options {
+		maxw = max(maxw, len([]rune(opt)))
+	}
+	return size.Const(maxw + 4)
+}
+
+func (radio *RadioGroup) Height() size.T {
+	return size.Const(len(radio.options))
+}
+
+func (radio *RadioGroup) Selected() (int, string) {
+	if radio.selected < 0 || radio.selected >= len(radio.options) {
+		return -1, ""
+	}
+	return radio.selected, radio.options[radio.selected]
+}
+
+func (radio *RadioGroup) SetSelected(i int) {
+	if i < 0 || i >= len(radio.options) || i == radio.selected {
+		return
+	}
+	radio.selected = i
+	radio.cursor = i
+	if radio.OnChange != nil {
+		radio.OnChange(i, radio.options[i])
+	}
+}
+
+func (radio *RadioGroup) CursorUp() {
+	if radio.cursor > 0 {
+		radio.cursor--
+	}
+}
+
+func (radio *RadioGroup) CursorDown() {
+	if radio.cursor < len(radio.options)-1 {
+		radio.cursor++
+	}
+}
+
+func (radio *RadioGroup) SelectCursor() {
+	radio.SetSelected(radio.cursor)
+}
+
+func (radio *RadioGroup) Render(canvas wind.Canvas) {
+	defer radio.drawLabel(canvas)
+	for y, opt := range radio.options {
+		fg := term.ColorDefault
+		bg := term.ColorDefault
+		if radio.IsDisabled() {
+			fg = term.ColorBlack | term.AttrBold
+		} else if radio.IsFocused() {
+			bg = term.ColorRed
+			if y == radio.cursor {
+				bg = term.ColorBlue
+			}
+		}
+		mark := ' '
+		if y == radio.selected {
+			mark = '*'
+		}
+		for x, c := range []rune("(" + string(mark) + ") " + opt) {
+			canvas.Draw(x, y, c, uint16(fg), uint16(bg))
+		}
+	}
+}
+
+func (radio *RadioGroup) DefaultKeys() control.Keymap {
+	return control.Keymap{
+		term.KeyArrowUp:   func(_ *control.Flow) { radio.CursorUp() },
+		term.KeyArrowDown: func(_ *control.Flow) { radio.CursorDown() },
+		term.KeySpace:     func(_ *control.Flow) { radio.SelectCursor() },
+		term.KeyEnter:     func(_ *control.Flow) { radio.SelectCursor() },
+	}
+}
+
+func (radio *RadioGroup) Control(flow *control.Flow) {
+	if radio.IsDisabled() {
+		return
+	}
+	flow.TermSwitch(control.Opts{}, radio.DefaultKeys())
+}
+
+func (radio *RadioGroup) Choose(flow *control.Flow) (int, string) {
+	radio.Control(flow)
+	return radio.Selected()
+}
@@ -17,6 +17,7 @@ const (
 	GtypeY
 	GtypeComp
 	GtypeNil
+	GtypeGrid
 )
 
 type Group interface {
@@ -37,6 +38,17 @@ type ComponentGroup interface {
 	Component() Component
 }
 
+// ContainerGroup is a group whose children can be changed after
+// it is created. Focusers watching the tree are told of the change,
+// and move the focus elsewhere if the current component is removed.
+type ContainerGroup interface {
+	Group
+	Append(elems ...Group)
+	Insert(i int, elems ...Group)
+	Remove(elem Group) bool
+	Replace(old, new Group) bool
+}
+
 type nilGroup struct {
 	parent Group
 	next   Group
@@ -84,6 +96,10 @@ type group struct {
 	prev     Group
 	children []Group
 	gtype    GroupType
+	focusers []*Focuser
+
+	// only for grids, children is then rows flattened
+	rows [][]Group
 }
 
 func (g *group) Group()            {}
@@ -97,35 +113,216 @@ func (g *group) SetNext(next Group)     { g.next = next }
 func (g *group) SetPrev(prev Group)     { g.prev = prev }
 func (g *group) Gtype() GroupType       { return g.gtype }
 
-func createGroup(gtype GroupType, elems []Group) Group {
-	g := &group{
-		gtype:    gtype,
-		children: elems,
-		parent:   NilGroup,
-		next:     NilGroup,
-		prev:     NilGroup,
+func walkGroup(g Group, fn func(Group)) {
+	if g == NilGroup {
+		return
 	}
+	fn(g)
+	for _, child := range g.Children() {
+		walkGroup(child, fn)
+	}
+}
 
-	var lastchild Group = NilGroup
+func (g *group) Append(elems ...Group) {
+	g.Insert(len(g.children), elems...)
+}
+
+// Insert puts elems before the i-th child. For grids,
+// elems are inserted as a new row before the i-th row.
+func (g *group) Insert(i int, elems ...Group) {
+	var moved []func()
 	for _, elem := range elems {
+		moved = append(moved, detach(elem))
+	}
+	if g.gtype == GtypeGrid {
+		i = g.insertRow(i, elems)
+	} else {
+		i = clamp(i, 0, len(g.children))
+		children := make([]Group, 0, len(g.children)+len(elems))
+		children = append(children, g.children[:i]...)
+		children = append(children, elems...)
+		children = append(children, g.children[i:]...)
+		g.children = children
+	}
+	g.relink()
+	g.changed(i)
+	for _, notify := range moved {
+		notify()
+	}
+}
+
+// insertRow returns the index of the first
+// child in the row in the flattened children
+func (g *group) insertRow(i int, elems []Group) int {
+	i = clamp(i, 0, len(g.rows))
+	rows := make([][]Group, 0, len(g.rows)+1)
+	rows = append(rows, g.rows[:i]...)
+	rows = append(rows, append([]Group(nil), elems...))
+	rows = append(rows, g.rows[i:]...)
+	g.rows = rows
+	g.children = flatten(rows)
+
+	index := 0
+	for _, row := range rows[:i] {
+		index += len(row)
+	}
+	return index
+}
+
+func (g *group) position(elem Group) (int, int) {
+	for y, row := range g.rows {
+		if x := indexOf(row, elem); x >= 0 {
+			return y, x
+		}
+	}
+	return -1, -1
+}
+
+func asGrid(g Group) (*group, bool) {
+	grid, ok := g.(*group)
+	return grid, ok && grid.gtype == GtypeGrid
+}
+
+func flatten(rows [][]Group) []Group {
+	var elems []Group
+	for _, row := range rows {
+		elems = append(elems, row...)
+	}
+	return elems
+}
+
+func (g *group) Remove(elem Group) bool {
+	i := indexOf(g.children, elem)
+	if i < 0 {
+		return false
+	}
+	g.remove(i)
+	g.changed(i)
+	return true
+}
+
+func (g *group) Replace(old, new Group) bool {
+	if old == new {
+		return indexOf(g.children, old) >= 0
+	}
+	if indexOf(g.children, old) < 0 {
+		return false
+	}
+	notify := detach(new)
+	i := indexOf(g.children, old)
+	g.children[i] = new
+	if y, x := g.position(old); y >= 0 {
+		g.rows[y][x] = new
+	}
+	unlink(old)
+	g.relink()
+	g.changed(i)
+	notify()
+	return true
+}
+
+func (g *group) remove(i int) {
+	elem := g.children[i]
+	g.children = append(g.children[:i:i], g.children[i+1:]...)
+	if y, x := g.position(elem); y >= 0 {
+		row := append(g.rows[y][:x:x], g.rows[y][x+1:]...)
+		if len(row) == 0 {
+			g.rows = append(g.rows[:y:y], g.rows[y+1:]...)
+		} else {
+			g.rows[y] = row
+		}
+	}
+	unlink(elem)
+	g.relink()
+}
+
+func indexOf(children []Group, elem Group) int {
+	for i, child := range children {
+		if child == elem {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *group) relink() {
+	var lastchild Group = NilGroup
+	for _, elem := range g.children {
 		elem.SetParent(g)
+		elem.SetNext(NilGroup)
 		if lastchild != NilGroup {
 			lastchild.SetNext(elem)
 		}
 		elem.SetPrev(lastchild)
 		lastchild = elem
 	}
+}
+
+// changed notifies the focusers of g and of its ancestors
+func (g *group) changed(i int) {
+	for p := Group(g); p != NilGroup; p = p.Parent() {
+		if p, ok := p.(*group); ok {
+			for _, foc := range p.focusers {
+				foc.groupChanged(g, i)
+			}
+		}
+	}
+}
+
+// detach removes elem from its current parent, if any, so that it can
+// be moved around within the tree. The returned function notifies
+// the old parent; it's called after elem has been put in its new place
+// so that the focus can stay on it.
+func detach(elem Group) func() {
+	parent, ok := elem.Parent().(*group)
+	if !ok {
+		return func() {}
+	}
+	i := indexOf(parent.children, elem)
+	if i < 0 {
+		return func() {}
+	}
+	parent.remove(i)
+	return func() { parent.changed(i) }
+}
+
+func unlink(elem Group) {
+	elem.SetParent(NilGroup)
+	elem.SetNext(NilGroup)
+	elem.SetPrev(NilGroup)
+}
+
+func createGroup(gtype GroupType, elems []Group) *group {
+	g := &group{
+		gtype:    gtype,
+		children: elems,
+		parent:   NilGroup,
+		next:     NilGroup,
+		prev:     NilGroup,
+	}
+	g.relink()
 	return g
 }
 
-func XGroup(elems ...Group) Group {
+func XGroup(elems ...Group) ContainerGroup {
 	return createGroup(GtypeX, elems)
 }
 
-func YGroup(elems ...Group) Group {
+func YGroup(elems ...Group) ContainerGroup {
 	return createGroup(GtypeY, elems)
 }
 
+// GridGroup arranges the groups in rows and columns. Moving the focus
+// up or down keeps to the same column, even across shorter rows.
+// Append and Insert add whole rows to a grid.
+func GridGroup(rows ...[]Group) ContainerGroup {
+	g := createGroup(GtypeGrid, flatten(rows))
+	for _, row := range rows {
+		g.rows = append(g.rows, append([]Group(nil), row...))
+	}
+	return g
+}
+
 func CompGroup(comp Component) Group {
 	return &gcomp{
 		component: comp,
@@ -136,38 +333,88 @@ func CompGroup(comp Component) Group {
 	}
 }
 
+// FocusChecker can be implemented by components that the Focuser
+// should skip over, such as labels, separators or disabled buttons.
+// Components without it are always focusable.
+type FocusChecker interface {
+	IsFocusable() bool
+}
+
 type Focusable struct {
-	focused bool
+	focused  bool
+	disabled bool
 }
 
 func (f *Focusable) Focus()          { f.focused = true }
 func (f *Focusable) Unfocus()        { f.focused = false }
 func (f *Focusable) IsFocused() bool { return f.focused }
 
+func (f *Focusable) Enable()           { f.disabled = false }
+func (f *Focusable) Disable()          { f.disabled = true }
+func (f *Focusable) IsDisabled() bool  { return f.disabled }
+func (f *Focusable) IsFocusable() bool { return !f.disabled }
+
+// FocusEnterHook and FocusLeaveHook can be implemented by
+// components that need to know when the Focuser moves to or away from them.
+type FocusEnterHook interface {
+	FocusEnter(from Component)
+}
+
+type FocusLeaveHook interface {
+	FocusLeave(to Component)
+}
+
+type FocusChangeFunc func(old, new Component)
+
 type focusIndex map[*group]Group
 
+type focusScope struct {
+	top     Group
+	current Group
+	watched bool
+}
+
 type Focuser struct {
+	top       Group
 	current   Group
 	lastFocus focusIndex
+	geometry  *Geometry
+
+	// the column to go back to when moving
+	// up or down a grid, past shorter rows
+	gridColumn map[*group]int
+	keepColumn bool
+
+	listeners []FocusChangeFunc
+	scopes    []focusScope
+	hints     []hint
+	hintInput string
+
+	// Wrap makes FocusNext and FocusPrev
+	// cycle around at the ends of the group tree
+	Wrap bool
 }
 
 func NewFocuser(g Group) *Focuser {
 	focuser := &Focuser{
+		top:       g,
 		current:   g,
 		lastFocus: make(focusIndex),
+
+		gridColumn: make(map[*group]int),
 	}
+	focuser.watch(g)
 	focuser.focusFirstComp()
 	return focuser
 }
 
 func (foc *Focuser) focusFirstComp() {
-	group := foc.current
-	if group.Gtype() != GtypeComp {
-		group = foc.searchFirstComponent(foc.current)
-	}
+	group := foc.searchFirstComponent(foc.current)
 	if group, ok := group.(ComponentGroup); ok {
 		foc.current = group
-		group.Component().Focus()
+		comp := group.Component()
+		comp.Focus()
+		foc.focusChanged(NilComponent, comp)
 	}
 }
 
@@ -180,9 +427,13 @@ func (foc *Focuser) setLastFocus() {
 		}
 		if g, ok := parent.(*group); ok {
 			foc.lastFocus[g] = current
+			if g.gtype == GtypeGrid && !foc.keepColumn {
+				_, foc.gridColumn[g] = g.position(current)
+			}
 		}
 		current = parent
 	}
+	foc.keepColumn = false
 }
 
 func (foc *Focuser) Current() Component {
@@ -194,100 +445,405 @@ func (foc *Focuser) Current() Component {
 
 func (foc *Focuser) setCurrent(group Group) Component {
 	if group, ok := group.(ComponentGroup); ok {
-		if current := foc.Current(); current != nil {
+		current := foc.Current()
+		if current != nil {
 			current.Unfocus()
 		}
 		foc.current = group
 		foc.setLastFocus()
 		comp := group.Component()
 		comp.Focus()
+		if comp != current {
+			foc.focusChanged(current, comp)
+		}
 		return comp
 	}
 	return NilComponent
 }
 
+// groupChanged is called when the children of g, at index i,
+// have been changed. If the current component was removed from the tree,
+// the focus goes to what took its place, or whatever is nearest.
+func (foc *Focuser) groupChanged(g Group, i int) {
+	for k, v := range foc.lastFocus {
+		if v.Parent() != k {
+			delete(foc.lastFocus, k)
+		}
+	}
+
+	old := foc.Current()
+	if old != NilComponent && foc.contains(foc.current) {
+		return
+	}
+	if old == NilComponent {
+		foc.setCurrent(foc.searchFirstComponent(foc.top))
+		return
+	}
+	foc.moveTo(foc.searchAround(g, i))
+}
+
+// moveTo is like setCurrent, but a NilGroup
+// leaves nothing focused instead of being ignored
+func (foc *Focuser) moveTo(group Group) Component {
+	if group == NilGroup {
+		old := foc.Current()
+		old.Unfocus()
+		foc.current = foc.top
+		if old != NilComponent {
+			foc.focusChanged(old, NilComponent)
+		}
+		return NilComponent
+	}
+	return foc.setCurrent(group)
+}
+
+// PushScope traps the focus inside g until PopScope is called,
+// as needed by dialogs and popup menus. The first component in g
+// is focused, and returned.
+func (foc *Focuser) PushScope(g Group) Component {
+	foc.scopes = append(foc.scopes, focusScope{
+		top:     foc.top,
+		current: foc.current,
+		watched: foc.watch(g),
+	})
+	foc.top = g
+	return foc.moveTo(foc.searchFirstComponent(g))
+}
+
+// PopScope leaves the scope of the last PushScope, and gives the focus
+// back to the component that had it before. NilComponent is returned
+// if there is no scope to leave.
+func (foc *Focuser) PopScope() Component {
+	n := len(foc.scopes)
+	if n == 0 {
+		return NilComponent
+	}
+	scope := foc.scopes[n-1]
+	foc.scopes = foc.scopes[:n-1]
+	if scope.watched {
+		foc.unwatch(foc.top)
+	}
+
+	foc.top = scope.top
+	group := scope.current
+	if !foc.contains(group) || !isFocusable(group) {
+		group = foc.searchFirstComponent(foc.top)
+	}
+	return foc.moveTo(group)
+}
+
+func (foc *Focuser) ScopeDepth() int {
+	return len(foc.scopes)
+}
+
+// watch makes the focuser be notified of changes in g,
+// false is returned if it already was (or can't be)
+func (foc *Focuser) watch(g Group) bool {
+	g_, ok := g.(*group)
+	if !ok {
+		return false
+	}
+	for _, f := range g_.focusers {
+		if f == foc {
+			return false
+		}
+	}
+	g_.focusers = append(g_.focusers, foc)
+	return true
+}
+
+func (foc *Focuser) unwatch(g Group) {
+	if g, ok := g.(*group); ok {
+		for i, f := range g.focusers {
+			if f == foc {
+				g.focusers = append(g.focusers[:i:i], g.focusers[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (foc *Focuser) searchAround(g Group, i int) Group {
+	if !foc.contains(g) {
+		return foc.searchFirstComponent(foc.top)
+	}
+	children := g.Children()
+	for j := i; j < len(children); j++ {
+		if found := foc.searchFirstInOrder(children[j]); found != NilGroup {
+			return found
+		}
+	}
+	for j := min(i, len(children)) - 1; j >= 0; j-- {
+		if found := foc.searchLastInOrder(children[j]); found != NilGroup {
+			return found
+		}
+	}
+	if g == foc.top {
+		return NilGroup
+	}
+	parent := g.Parent()
+	return foc.searchAround(parent, indexOf(parent.Children(), g)+1)
+}
+
+func (foc *Focuser) contains(g Group) bool {
+	for ; g != NilGroup; g = g.Parent() {
+		if g == foc.top {
+			return true
+		}
+	}
+	return false
+}
+
+// FocusByName moves the focus to the component with the given name.
+// NilComponent is returned, and the focus stays, if it isn't found
+// or can't be focused.
+func (foc *Focuser) FocusByName(name string) Component {
+	group := findGroup(foc.top, nameMatcher(name))
+	if !isFocusable(group) {
+		return NilComponent
+	}
+	return foc.setCurrent(group)
+}
+
+// OnFocusChange adds a function that is called
+// whenever the current component changes.
+func (foc *Focuser) OnFocusChange(fn FocusChangeFunc) {
+	foc.listeners = append(foc.listeners, fn)
+}
+
+func (foc *Focuser) focusChanged(old, new Component) {
+	if hook, ok := old.(FocusLeaveHook); ok {
+		hook.FocusLeave(new)
+	}
+	if hook, ok := new.(FocusEnterHook); ok {
+		hook.FocusEnter(old)
+	}
+	for _, fn := range foc.listeners {
+		fn(old, new)
+	}
+}
+
+// UseGeometry makes the directional focus methods pick the nearest
+// component on screen instead of following the group structure.
+// Until the current component has been rendered, the group structure
+// is still used. Pass nil to switch back.
+func (foc *Focuser) UseGeometry(geo *Geometry) {
+	foc.geometry = geo
+}
+
+func (foc *Focuser) searchDir(dir direction, gtype GroupType, search func(GroupType, Group) Group) Group {
+	if foc.geometry != nil {
+		if group, ok := foc.geometry.searchNearest(dir, foc.current, foc.top); ok {
+			return group
+		}
+	}
+	return search(gtype, foc.current)
+}
+
 func (foc *Focuser) FocusUp() Component {
-	group := foc.searchPrev(GtypeY, foc.current)
+	group := foc.searchDir(dirUp, GtypeY, foc.searchPrev)
 	return foc.setCurrent(group)
 }
 
 func (foc *Focuser) FocusDown() Component {
-	group := foc.searchNext(GtypeY, foc.current)
+	group := foc.searchDir(dirDown, GtypeY, foc.searchNext)
 	return foc.setCurrent(group)
 }
 
 func (foc *Focuser) FocusLeft() Component {
-	group := foc.searchPrev(GtypeX, foc.current)
+	group := foc.searchDir(dirLeft, GtypeX, foc.searchPrev)
 	return foc.setCurrent(group)
 }
 
 func (foc *Focuser) FocusRight() Component {
-	group := foc.searchNext(GtypeX, foc.current)
+	group := foc.searchDir(dirRight, GtypeX, foc.searchNext)
 	return foc.setCurrent(group)
 }
 
+// FocusNext moves the focus to the next component
+// in document order (depth-first), like Tab does.
+func (foc *Focuser) FocusNext() Component {
+	group := foc.searchLinear(foc.current, Group.Next, foc.searchFirstInOrder)
+	return foc.setCurrent(group)
+}
+
+// FocusPrev is the Shift-Tab counterpart of FocusNext.
+func (foc *Focuser) FocusPrev() Component {
+	group := foc.searchLinear(foc.current, Group.Prev, foc.searchLastInOrder)
+	return foc.setCurrent(group)
+}
+
+func (foc *Focuser) searchLinear(
+	group Group,
+	sibling func(Group) Group,
+	search func(Group) Group,
+) Group {
+	if group.Gtype() != GtypeComp {
+		return search(group)
+	}
+	for g := group; g != NilGroup && g != foc.top; g = g.Parent() {
+		for sib := sibling(g); sib != NilGroup; sib = sibling(sib) {
+			if found := search(sib); found != NilGroup {
+				return found
+			}
+		}
+	}
+	if foc.Wrap {
+		return search(foc.top)
+	}
+	return NilGroup
+}
+
 func (foc *Focuser) searchPrev(gtype GroupType, group Group) Group {
-	if group == NilGroup {
-		return group
+	if group == NilGroup || group == foc.top {
+		return NilGroup
 	}
 
-	parentType := group.Parent().Gtype()
-	if parentType == gtype && group.Prev() != NilGroup {
-		return foc.searchLastComponent(group.Prev())
+	if grid, ok := asGrid(group.Parent()); ok {
+		if found := foc.searchGrid(grid, group, gtype, -1); found != NilGroup {
+			return found
+		}
+	} else if group.Parent().Gtype() == gtype {
+		for prev := group.Prev(); prev != NilGroup; prev = prev.Prev() {
+			if found := foc.searchLastComponent(prev); found != NilGroup {
+				return found
+			}
+		}
 	}
 
 	return foc.searchPrev(gtype, group.Parent())
 }
 
 func (foc *Focuser) searchNext(gtype GroupType, group Group) Group {
-	if group == NilGroup {
-		return group
+	if group == NilGroup || group == foc.top {
+		return NilGroup
 	}
 
-	parentType := group.Parent().Gtype()
-	if parentType == gtype && group.Next() != NilGroup {
-		return foc.searchFirstComponent(group.Next())
+	if grid, ok := asGrid(group.Parent()); ok {
+		if found := foc.searchGrid(grid, group, gtype, 1); found != NilGroup {
+			return found
+		}
+	} else if group.Parent().Gtype() == gtype {
+		for next := group.Next(); next != NilGroup; next = next.Next() {
+			if found := foc.searchFirstComponent(next); found != NilGroup {
+				return found
+			}
+		}
 	}
 
 	return foc.searchNext(gtype, group.Parent())
 }
 
-func (foc *Focuser) searchComponent(g Group, indexOf func([]Group) int) Group {
-	for {
-		gt := g.Gtype()
-		if gt == GtypeComp || gt == GtypeNil {
-			break
+// searchGrid looks for the next component in the grid,
+// step is -1 or 1 for the direction.
+func (foc *Focuser) searchGrid(grid *group, cell Group, gtype GroupType, step int) Group {
+	search := foc.searchFirstComponent
+	if step < 0 {
+		search = foc.searchLastComponent
+	}
+
+	y, x := grid.position(cell)
+	if gtype == GtypeX {
+		row := grid.rows[y]
+		for x += step; x >= 0 && x < len(row); x += step {
+			if found := search(row[x]); found != NilGroup {
+				return found
+			}
 		}
+		return NilGroup
+	}
 
-		if subg, ok := g.(*group); ok {
-			if g_, ok := foc.lastFocus[subg]; ok {
-				g = g_
+	if col, ok := foc.gridColumn[grid]; ok {
+		x = col
+	}
+	for y += step; y >= 0 && y < len(grid.rows); y += step {
+		if found := foc.searchRow(grid.rows[y], x); found != NilGroup {
+			foc.keepColumn = true
+			return found
+		}
+	}
+	return NilGroup
+}
+
+// searchRow tries the cells nearest to column x first,
+// the one on the left when tied
+func (foc *Focuser) searchRow(row []Group, x int) Group {
+	x = min(x, len(row)-1)
+	for d := 0; d < len(row); d++ {
+		for _, i := range []int{x - d, x + d} {
+			if i < 0 || i >= len(row) {
 				continue
 			}
+			if found := foc.searchFirstComponent(row[i]); found != NilGroup {
+				return found
+			}
 		}
+	}
+	return NilGroup
+}
+
+// searchComponent returns the first (or last, if reverse is set)
+// focusable component in g, or NilGroup if there is none
+func (foc *Focuser) searchComponent(g Group, reverse bool, remember bool) Group {
+	switch g.Gtype() {
+	case GtypeNil:
+		return NilGroup
+	case GtypeComp:
+		if !isFocusable(g) {
+			return NilGroup
+		}
+		return g
+	}
 
-		children := g.Children()
-		n := len(children)
-		if n == 0 {
-			g = NilGroup
-			break
+	if subg, ok := g.(*group); ok && remember {
+		if g_, ok := foc.lastFocus[subg]; ok {
+			if found := foc.searchComponent(g_, reverse, remember); found != NilGroup {
+				return found
+			}
 		}
-		i := indexOf(children)
-		g = children[i]
 	}
-	return g
+
+	children := g.Children()
+	n := len(children)
+	for i := 0; i < n; i++ {
+		child := children[i]
+		if reverse {
+			child = children[n-i-1]
+		}
+		if found := foc.searchComponent(child, reverse, remember); found != NilGroup {
+			return found
+		}
+	}
+	return NilGroup
+}
+
+func isFocusable(g Group) bool {
+	cgroup, ok := g.(ComponentGroup)
+	if !ok {
+		return false
+	}
+	if comp, ok := cgroup.Component().(FocusChecker); ok {
+		return comp.IsFocusable()
+	}
+	return true
 }
 
 func (foc *Focuser) searchFirstComponent(group Group) Group {
-	return foc.searchComponent(group, func(_ []Group) int {
-		return 0
-	})
+	return foc.searchComponent(group, false, true)
 }
 
 func (foc *Focuser) searchLastComponent(group Group) Group {
-	return foc.searchComponent(group, func(children []Group) int {
-		return len(children) - 1
-	})
+	return foc.searchComponent(group, true, true)
+}
+
+// the lastFocus memory is skipped here,
+// otherwise tabbing would jump over components
+func (foc *Focuser) searchFirstInOrder(group Group) Group {
+	return foc.searchComponent(group, false, false)
+}
+
+func (foc *Focuser) searchLastInOrder(group Group) Group {
+	return foc.searchComponent(group, true, false)
 }
 
 type Component interface {
@@ -296,16 +852,9 @@ type Component interface {
 	//Control(tun *tool.Tun)
 	Control(flow *control.Flow)
 
-	//Name() string
-	//SetName(name string) Component
-
 	Focus()
 	Unfocus()
 	IsFocused() bool
-	//IsFocusable() bool
-
-	//Label(label string)
-	//Unlabel()
 }
 
 type nilComp struct {
@@ -325,6 +874,66 @@ func (_ *nilComp) IsFocused() bool {
 
 var NilComponent = new(nilComp)
 
+// NamedComponent is implemented by components that have
+// an ID, which can be looked up with FindByName.
+type NamedComponent interface {
+	Component
+	Name() string
+}
+
+type Nameable struct {
+	name string
+}
+
+func (n *Nameable) Name() string        { return n.name }
+func (n *Nameable) SetName(name string) { n.name = name }
+
+// FindByName returns the first component in g with the given name,
+// or NilComponent if there is none.
+func FindByName(g Group, name string) Component {
+	if g := findGroup(g, nameMatcher(name)); g != NilGroup {
+		return g.(ComponentGroup).Component()
+	}
+	return NilComponent
+}
+
+// FindComponents returns all components in g,
+// in document order, for which match returns true.
+// To find all Listboxes, for instance:
+//
+//	FindComponents(g, func(c Component) bool {
+//		_, ok := c.(*Listbox)
+//		return ok
+//	})
+func FindComponents(g Group, match func(Component) bool) []Component {
+	var comps []Component
+	walkGroup(g, func(g Group) {
+		if cgroup, ok := g.(ComponentGroup); ok && match(cgroup.Component()) {
+			comps = append(comps, cgroup.Component())
+		}
+	})
+	return comps
+}
+
+func findGroup(g Group, match func(Component) bool) Group {
+	found := Group(NilGroup)
+	walkGroup(g, func(g Group) {
+		if cgroup, ok := g.(ComponentGroup); ok && found == NilGroup {
+			if match(cgroup.Component()) {
+				found = g
+			}
+		}
+	})
+	return found
+}
+
+func nameMatcher(name string) func(Component) bool {
+	return func(comp Component) bool {
+		named, ok := comp.(NamedComponent)
+		return ok && named.Name() == name
+	}
+}
+
 type Sizable struct {
 	w, h     int
 	AutoSize bool
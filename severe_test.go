@@ -6,8 +6,13 @@ import (
 	//sevtool "github.com/nvlled/severe/tool"
 	"github.com/nvlled/control"
 	"github.com/nvlled/wind"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TODO: Handle timeout on tests
@@ -356,29 +361,30 @@ func TestSevere1(t *testing.T) {
 			})
 	}
 
-	layer := wind.Vlayer(
-		wind.Hlayer(
-			wind.Vlayer(
+	layer := Vlayer(
+		Hlayer(
+			Vlayer(
 				wind.Text("editor"),
-				wind.Border('.', '.', editor),
+				Border('.', '.', editor),
 				editBtn,
 			),
 			wind.LineV(' '),
-			wind.Vlayer(
+			Vlayer(
 				wind.Text("color"),
-				wind.Border('.', '.', colorList),
+				Border('.', '.', colorList),
 				setColorBtn,
 			),
 		),
 		wind.LineH('─'),
 		wind.Text(`
-		** Arrow keys to move focus
+		** Arrow keys or Tab to move focus
+		** Ctrl-f to jump to a component by its label
 		** Enter to control focused component
 		** Esc to stop component control
 		** Ctrl-c to exit`),
 	)
 
-	layer = wind.TapRender(layer, func(layer wind.Layer, canvas wind.Canvas) {
+	layer = TapRender(layer, func(layer wind.Layer, canvas wind.Canvas) {
 		canvas = wind.ChangeDefaultColor(color, 0, canvas)
 		layer.Render(canvas)
 	})
@@ -390,9 +396,7 @@ func TestSevere1(t *testing.T) {
 		term.Flush()
 	}
 
-	// TODO:
-	// focuser := NewFocuser(extractGroup(layer))
-	focuser := NewFocuser(XGroup(CompGroup(editBtn), CompGroup(setColorBtn)))
+	focuser := NewFocuser(ExtractGroup(layer))
 
 	drawLayer()
 
@@ -404,6 +408,14 @@ func TestSevere1(t *testing.T) {
 		},
 		func(flow *control.Flow, e term.Event) {
 			switch e.Key {
+			case term.KeyTab:
+				focuser.FocusNext()
+			case term.KeyCtrlF:
+				focuser.ChooseHint(flow)
+			case term.KeyArrowUp:
+				focuser.FocusUp()
+			case term.KeyArrowDown:
+				focuser.FocusDown()
 			case term.KeyArrowLeft:
 				focuser.FocusLeft()
 			case term.KeyArrowRight:
@@ -428,6 +440,855 @@ func TestSevere1(t *testing.T) {
 	term.Close()
 }
 
+func TestCheckbox(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	bold := NewCheckbox("bold", false)
+	underline := NewCheckbox("underline", true)
+	underline.Disable()
+	align := NewRadioGroup("left", "center", "right")
+
+	bold.OnChange = func(checked bool) {
+		if checked {
+			underline.Enable()
+		} else {
+			underline.Disable()
+		}
+	}
+
+	layer := Vlayer(
+		bold,
+		underline,
+		wind.LineH('-'),
+		align,
+		wind.LineH('-'),
+		wind.Text(`
+		** Arrow keys to move focus
+		** Space/Enter to toggle
+		** Ctrl-c to exit`),
+	)
+	drawLayer := func() {
+		term.Clear(0, 0)
+		layer.Render(canvas)
+		term.Flush()
+	}
+
+	focuser := NewFocuser(ExtractGroup(layer))
+	drawLayer()
+
+	control.TermStart(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow, e term.Event) {
+			switch e.Key {
+			case term.KeyArrowUp:
+				if focuser.Current() == align && align.cursor > 0 {
+					align.CursorUp()
+				} else {
+					focuser.FocusUp()
+				}
+			case term.KeyArrowDown:
+				if focuser.Current() == align && align.cursor < 2 {
+					align.CursorDown()
+				} else {
+					focuser.FocusDown()
+				}
+			case term.KeySpace, term.KeyEnter:
+				switch comp := focuser.Current().(type) {
+				case *Checkbox:
+					comp.Toggle()
+				case *RadioGroup:
+					comp.SelectCursor()
+				}
+			}
+		},
+	)
+
+	term.Close()
+	_, alignment := align.Selected()
+	println("bold:", bold.Checked(), "underline:", underline.Checked(), "align:", alignment)
+}
+
+func TestProgress(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	progress := NewProgressBar(40, 50)
+	progress.SetLabel("copying")
+	spinner := NewSpinner("working...")
+
+	layer := wind.Vlayer(
+		progress,
+		spinner,
+		wind.Text(`
+		** Ctrl-c to exit`),
+	)
+	drawLayer := func() {
+		term.Clear(0, 0)
+		layer.Render(canvas)
+		term.Flush()
+	}
+
+	spinner.Start()
+	go func() {
+		for !progress.Done() {
+			time.Sleep(100 * time.Millisecond)
+			progress.Add(1)
+		}
+		spinner.Stop()
+		spinner.SetLabel("done")
+	}()
+
+	drawLayer()
+	control.New(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow) {
+			flow.TermTransfer(control.Opts{}, func(_ *control.Flow, _ term.Event) {})
+		})
+
+	spinner.Stop()
+	term.Close()
+}
+
+func TestDialog(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	status := NewLess(40, 1)
+	status.SetText("nothing yet")
+	overlay := NewOverlay(wind.Vlayer(
+		status,
+		wind.Text(`
+		** m for a message box
+		** c to confirm
+		** i to input some text
+		** Ctrl-c to exit`),
+	))
+	drawLayer := func() {
+		term.Clear(0, 0)
+		overlay.Render(canvas)
+		term.Flush()
+	}
+
+	drawLayer()
+	control.TermStart(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow, e term.Event) {
+			switch e.Ch {
+			case 'm':
+				MessageBox(flow, overlay, "Hello", "This is a message box.\nEnter or Esc to close.")
+			case 'c':
+				if Confirm(flow, overlay, "Confirm", "Are you sure?") {
+					status.SetText("confirmed")
+				} else {
+					status.SetText("not confirmed")
+				}
+			case 'i':
+				if text, ok := InputDialog(flow, overlay, "Input", "Your name:", "anon"); ok {
+					status.SetText("name: " + text)
+				} else {
+					status.SetText("input cancelled")
+				}
+			}
+		},
+	)
+
+	term.Close()
+}
+
+func TestMenu(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	status := NewLess(40, 1)
+	status.SetText("nothing yet")
+	say := func(text string) func(*control.Flow) {
+		return func(_ *control.Flow) { status.SetText(text) }
+	}
+
+	overlay := NewOverlay(nil)
+	bar := NewMenuBar(overlay,
+		NewMenu("File",
+			NewMenuItem("New", term.KeyCtrlN, say("new file")),
+			NewMenuItem("Open...", term.KeyCtrlO, say("open file")),
+			SubmenuItem("Recent", NewMenu("Recent",
+				NewMenuItem("a.txt", 0, say("opened a.txt")),
+				NewMenuItem("b.txt", 0, say("opened b.txt")),
+			)),
+			Separator(),
+			NewMenuItem("Quit", term.KeyF4, say("no quitting, use Ctrl-c")),
+		),
+		NewMenu("Edit",
+			NewMenuItem("Copy", term.KeyF5, say("copied")),
+			NewMenuItem("Paste", term.KeyF6, say("pasted")),
+		),
+	)
+	overlay.SetBase(wind.Vlayer(
+		bar,
+		status,
+		wind.Text(`
+		** F10 to open the menu bar
+		** Ctrl-n, Ctrl-o, F4, F5 and F6 are accelerators
+		** Ctrl-c to exit`),
+	))
+	drawLayer := func() {
+		term.Clear(0, 0)
+		overlay.Render(canvas)
+		term.Flush()
+	}
+
+	drawLayer()
+	keymap := bar.Keymap()
+	control.TermStart(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow, e term.Event) {
+			if fn, ok := keymap[e.Key]; ok {
+				fn(flow)
+			}
+		},
+	)
+
+	term.Close()
+}
+
+func TestTable(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	var rows RowSlice
+	for i := 1; i <= 200; i++ {
+		rows = append(rows, []string{
+			fmt.Sprintf("file%03d.txt", i),
+			fmt.Sprintf("%d", (i*7919)%5000),
+			fmt.Sprintf("2015-%02d-%02d", i%12+1, i%28+1),
+			strings.Repeat("lorem ipsum ", i%5+1),
+		})
+	}
+	table := NewTable(0, 0, []Column{
+		{Title: "Name", Min: 12},
+		{Title: "Size", Align: AlignRight, Min: 6},
+		{Title: "Modified", Width: 10},
+		{Title: "Description", Flex: 1, Min: 20},
+	}, rows)
+	table.AutoSize = true
+	table.Focus()
+
+	layer := wind.Vlayer(
+		wind.Border('-', '|', wind.Size(50, 12, table)),
+		wind.Text(`** Ctrl-c to exit`),
+		wind.Text(`** Enter to select`),
+		wind.Text(`** s to sort by the selected column`),
+	)
+
+	drawLayer := func() {
+		term.Clear(0, 0)
+		layer.Render(canvas)
+		term.Flush()
+	}
+
+	cancelled := false
+	control.New(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt: control.Interrupts(
+				control.KeyInterrupt(term.KeyEnter),
+				func(e interface{}, ir control.Irctrl) {
+					if e, ok := e.(term.Event); ok && e.Key == term.KeyCtrlC {
+						cancelled = true
+						ir.Stop()
+					}
+				},
+			),
+		},
+		func(flow *control.Flow) {
+			drawLayer()
+			table.Control(flow)
+		},
+	)
+
+	term.Close()
+
+	if cancelled {
+		println("table cancelled!")
+	} else {
+		i, row := table.SelectedRow()
+		fmt.Println("table selected:", i, row)
+	}
+}
+
+func TestTreeView(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	var dirItem func(dir string) *TreeItem
+	dirItem = func(dir string) *TreeItem {
+		return &TreeItem{
+			Label: filepath.Base(dir),
+			Load: func() []*TreeItem {
+				infos, _ := ioutil.ReadDir(dir)
+				var items []*TreeItem
+				for _, info := range infos {
+					if info.IsDir() {
+						items = append(items, dirItem(filepath.Join(dir, info.Name())))
+					} else {
+						items = append(items, &TreeItem{Label: info.Name()})
+					}
+				}
+				return items
+			},
+		}
+	}
+
+	wd, _ := os.Getwd()
+	tree := NewTreeView(0, 0, dirItem(filepath.Dir(wd)))
+	tree.AutoSize = true
+
+	layer := wind.Vlayer(
+		wind.Border('-', '|', wind.Size(40, 15, tree)),
+		wind.Text(`** Ctrl-c to exit`),
+		wind.Text(`** Enter to select`),
+		wind.Text(`** Left/Right or Space to collapse and expand`),
+	)
+
+	drawLayer := func() {
+		term.Clear(0, 0)
+		layer.Render(canvas)
+		term.Flush()
+	}
+
+	cancelled := false
+	control.New(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt: control.Interrupts(
+				control.KeyInterrupt(term.KeyEnter),
+				func(e interface{}, ir control.Irctrl) {
+					if e, ok := e.(term.Event); ok && e.Key == term.KeyCtrlC {
+						cancelled = true
+						ir.Stop()
+					}
+				},
+			),
+		},
+		func(flow *control.Flow) {
+			drawLayer()
+			tree.Control(flow)
+		},
+	)
+
+	term.Close()
+
+	if cancelled {
+		println("tree view cancelled!")
+	} else if node := tree.Selected(); node != nil {
+		println("tree view selected:", node.Text())
+	}
+}
+
+func TestFilePicker(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	picker := NewFilePicker(0, 0, ".")
+	picker.AutoSize = true
+	picker.Pattern = "*.go"
+	picker.Focus()
+
+	layer := wind.Vlayer(
+		wind.Border('-', '|', wind.Size(40, 15, picker)),
+		wind.Text(`** Ctrl-c or Esc to exit`),
+		wind.Text(`** Enter to open a directory or select a file`),
+		wind.Text(`** Backspace to go up, . to show hidden files`),
+	)
+
+	drawLayer := func() {
+		term.Clear(0, 0)
+		layer.Render(canvas)
+		term.Flush()
+	}
+
+	var path string
+	var ok bool
+	control.New(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow) {
+			drawLayer()
+			path, ok = picker.Choose(flow)
+		},
+	)
+
+	term.Close()
+
+	if ok {
+		println("file picker selected:", path)
+	} else {
+		println("file picker cancelled!")
+	}
+}
+
+func TestStatusBar(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	notes := NewTextbox(30, 5)
+	notes.SetName("notes")
+	notes.SetBuffer("")
+	title := Textfield(30)
+	title.SetName("title")
+	title.SetBuffer("")
+
+	layer := Vlayer(
+		Border('-', '|', title),
+		Border('-', '|', notes),
+	)
+	focuser := NewFocuser(ExtractGroup(layer))
+	focuser.Wrap = true
+
+	mode := NewSegment("EDIT")
+	clock := NewSegment(time.Now().Format("15:04:05"))
+	status := NewStatusBar(50)
+	status.AddLeft(mode, FocusSegment(focuser))
+	status.AddCenter(clock)
+	status.AddRight(CursorSegment(notes))
+
+	go func() {
+		for now := range time.Tick(time.Second) {
+			clock.Set(now.Format("15:04:05"))
+		}
+	}()
+
+	layer = wind.Vlayer(
+		layer,
+		status,
+		wind.Text("** Tab to switch fields"),
+		wind.Text("** Insert to toggle the mode"),
+		wind.Text("** Ctrl-c to exit"),
+	)
+	drawLayer := func() {
+		term.Clear(0, 0)
+		layer.Render(canvas)
+		term.Flush()
+	}
+
+	drawLayer()
+	readonly := false
+	control.TermStart(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow, e term.Event) {
+			switch e.Key {
+			case term.KeyTab:
+				focuser.FocusNext()
+			case term.KeyInsert:
+				readonly = !readonly
+				if readonly {
+					mode.Set("VIEW")
+				} else {
+					mode.Set("EDIT")
+				}
+			default:
+				if tbox, ok := focuser.Current().(*Textbox); ok && !readonly {
+					tbox.HandleKey(e)
+				}
+			}
+		},
+	)
+
+	term.Close()
+}
+
+func TestSplitPane(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	term.SetInputMode(term.InputEsc | term.InputMouse)
+	canvas := wind.NewTermCanvas()
+
+	lbox := NewListbox(0, 0, ItemSlice{"one", "two", "three", "four", "five"})
+	lbox.AutoSize = true
+	tbox := NewTextbox(0, 0)
+	tbox.AutoSize = true
+	tbox.SetBuffer("resize the panes\nand this reflows")
+	less := NewLess(0, 0)
+	less.AutoSize = true
+	less.SetText("Lorem ipsum dolor sit amet, consectetur adipiscing elit, " +
+		"sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.")
+
+	geo := NewGeometry()
+	right := VSplit(0, 0, tbox, less)
+	right.AutoSize = true
+	right.MinFirst = 2
+	right.Geometry = geo
+	split := HSplit(60, 16, lbox, geo.Track(right))
+	split.MinFirst, split.MinSecond = 8, 10
+	split.Geometry = geo
+
+	layer := geo.Layer(Vlayer(
+		geo.Track(split),
+		wind.Text("** Tab to move the focus"),
+		wind.Text("** Enter on a divider, then arrow keys or the mouse to move it"),
+		wind.Text("** 1 and 2 collapse a side, Enter or Esc when done"),
+		wind.Text("** Ctrl-c to exit"),
+	))
+	focuser := NewFocuser(ExtractGroup(layer))
+	focuser.Wrap = true
+
+	drawLayer := func() {
+		term.Clear(0, 0)
+		layer.Render(canvas)
+		term.Flush()
+	}
+
+	drawLayer()
+	control.TermStart(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow, e term.Event) {
+			switch comp := focuser.Current().(type) {
+			case *SplitPane:
+				if e.Key == term.KeyEnter {
+					comp.Control(flow)
+					return
+				}
+			case *Textbox:
+				if e.Key != term.KeyTab {
+					comp.HandleKey(e)
+					return
+				}
+			}
+			if e.Key == term.KeyTab {
+				focuser.FocusNext()
+			}
+		},
+	)
+
+	term.Close()
+}
+
+func TestForm(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	form := NewForm()
+	form.Add("name", "Name", NewTextInput(20, ""), Required)
+	form.Add("email", "Email", NewTextInput(20, ""), Required, func(v interface{}) error {
+		if !strings.Contains(v.(string), "@") {
+			return fmt.Errorf("not an email address")
+		}
+		return nil
+	})
+	form.Add("admin", "Admin", NewCheckField("can delete things", false))
+	form.Add("role", "Role", NewSelectField(20, 3, ItemSlice{"guest", "user", "admin"}))
+	form.Validate(func(values map[string]interface{}) error {
+		if values["admin"] == true && values["role"] != "admin" {
+			return &FieldError{"role", "only for the admin role"}
+		}
+		return nil
+	})
+
+	layer := wind.Vlayer(
+		form,
+		wind.Text(`** Tab or the arrow keys to move between the fields`),
+		wind.Text(`** Space to check, Enter on a button`),
+		wind.Text(`** Esc or Ctrl-c to exit`),
+	)
+	drawLayer := func() {
+		term.Clear(0, 0)
+		layer.Render(canvas)
+		term.Flush()
+	}
+
+	var values map[string]interface{}
+	var ok bool
+	control.New(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow) {
+			drawLayer()
+			values, ok = form.Run(flow)
+		},
+	)
+
+	term.Close()
+
+	if ok {
+		fmt.Println("form submitted:", values)
+	} else {
+		println("form cancelled!")
+	}
+}
+
+func TestStructForm(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	config := struct {
+		Host    string  `severe:"label=Host name,required"`
+		Port    uint16  `severe:"width=6"`
+		Timeout float64 `severe:"label=Timeout (s),width=6"`
+		Mode    string  `severe:"enum=fast|safe|paranoid"`
+		Verbose bool
+		secret  string
+	}{Host: "localhost", Port: 8080, Timeout: 2.5, Mode: "safe"}
+
+	form, err := StructForm(&config)
+	if err != nil {
+		term.Close()
+		t.Fatal(err)
+	}
+
+	layer := wind.Vlayer(
+		form,
+		wind.Text(`** Tab or the arrow keys to move between the fields`),
+		wind.Text(`** Esc or Ctrl-c to exit`),
+	)
+	drawLayer := func() {
+		term.Clear(0, 0)
+		layer.Render(canvas)
+		term.Flush()
+	}
+
+	var values map[string]interface{}
+	var ok bool
+	control.New(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow) {
+			drawLayer()
+			values, ok = form.Run(flow)
+		},
+	)
+
+	term.Close()
+
+	if ok {
+		if err := StoreValues(&config, values); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Printf("config: %+v\n", config)
+	} else {
+		println("form cancelled!")
+	}
+}
+
+func TestComboBox(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	fruits := []string{"apple", "apricot", "banana", "blueberry", "cherry",
+		"grape", "lemon", "mango", "papaya", "pineapple", "plum"}
+	var picked []string
+	combo := NewComboBox(20, ItemsFn(func() []string {
+		// what was picked before is suggested too
+		return append(append([]string{}, picked...), fruits...)
+	}))
+	combo.Focus()
+	status := NewLess(40, 1)
+	status.SetText("type to get suggestions")
+
+	geo := NewGeometry()
+	overlay := NewOverlay(geo.Layer(wind.Vlayer(
+		geo.Track(combo),
+		status,
+		wind.Text(`
+		** Down to show the suggestions, Enter to accept one
+		** Enter again to pick the text, even if it's not a fruit
+		** Esc or Ctrl-c to exit`),
+	)))
+	combo.Overlay = overlay
+	combo.Geometry = geo
+
+	drawLayer := func() {
+		term.Clear(0, 0)
+		overlay.Render(canvas)
+		term.Flush()
+	}
+
+	control.New(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow) {
+			drawLayer()
+			for {
+				text, ok := combo.Choose(flow)
+				if !ok {
+					return
+				}
+				picked = append(picked, text)
+				status.SetText("picked: " + text)
+				combo.SetText("")
+			}
+		},
+	)
+
+	term.Close()
+}
+
+func TestCommandPalette(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	fruits := []string{"apple", "banana", "cherry", "grape", "lemon", "mango"}
+	lbox := NewListbox(20, 6, ItemSlice(fruits))
+	status := NewLess(40, 1)
+	status.SetText("nothing yet")
+	say := func(text string) func(*control.Flow) {
+		return func(_ *control.Flow) { status.SetText(text) }
+	}
+
+	overlay := NewOverlay(nil)
+	palette := NewCommandPalette(overlay)
+	palette.Register("Say hello", say("hello"))
+	palette.Register("Clear status", say(""))
+	palette.Register("Open file", say("open file")).Key = term.KeyCtrlO
+	palette.Register("Open recent file", say("open recent file"))
+	palette.Register("Toggle word wrap", say("word wrap toggled")).Description = "View"
+	palette.Register("Pick a fruit", func(flow *control.Flow) {
+		_, item := lbox.Choose(flow)
+		status.SetText("picked " + item)
+	})
+	palette.RegisterKeymap("Fruits", lbox.DefaultKeys(), map[term.Key]string{
+		term.KeyArrowUp:   "Select previous fruit",
+		term.KeyArrowDown: "Select next fruit",
+	})
+
+	overlay.SetBase(wind.Vlayer(
+		status,
+		lbox,
+		wind.Text(`
+		** Ctrl-p to open the palette, type to filter
+		** Enter runs a command, the last ones used come first
+		** Ctrl-c to exit`),
+	))
+	drawLayer := func() {
+		term.Clear(0, 0)
+		overlay.Render(canvas)
+		term.Flush()
+	}
+
+	drawLayer()
+	keymap := palette.Keymap()
+	control.TermStart(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow, e term.Event) {
+			if fn, ok := keymap[e.Key]; ok {
+				fn(flow)
+			}
+		},
+	)
+
+	term.Close()
+}
+
+func TestCalendar(t *testing.T) {
+	defer antiFuck()
+	term.Init()
+	canvas := wind.NewTermCanvas()
+
+	now := time.Now()
+	cal := NewCalendar(now)
+	cal.Min = now.AddDate(0, -2, 0)
+	cal.Max = now.AddDate(0, 2, 0)
+	cal.ShowTime = true
+	cal.Focus()
+	status := NewLess(40, 1)
+	cal.OnChange = func(t time.Time) {
+		status.SetText(t.Format("Mon Jan 2 2006 15:04"))
+	}
+	cal.OnChange(cal.Time())
+
+	overlay := NewOverlay(wind.Vlayer(
+		status,
+		cal,
+		wind.Text(`
+		** arrows move between the days, PgUp and PgDn between the months
+		** Tab to change the hour and minute with Up and Down
+		** Enter picks the date and opens it in a dialog
+		** Esc or Ctrl-c to exit`),
+	))
+	drawLayer := func() {
+		term.Clear(0, 0)
+		overlay.Render(canvas)
+		term.Flush()
+	}
+
+	control.New(
+		control.TermSource,
+		control.Opts{
+			EventEnded: func(_ interface{}) { drawLayer() },
+			Interrupt:  control.KeyInterrupt(term.KeyCtrlC),
+		},
+		func(flow *control.Flow) {
+			drawLayer()
+			for {
+				picked, ok := cal.Choose(flow)
+				if !ok {
+					return
+				}
+				other := NewCalendar(picked)
+				other.FirstDay = time.Monday
+				if picked, ok = DateDialog(flow, overlay, "Another date", other); ok {
+					status.SetText("picked " + picked.Format("Mon Jan 2 2006"))
+				}
+			}
+		},
+	)
+
+	term.Close()
+}
+
 func colorValue(name string) term.Attribute {
 	switch name {
 	case "default":
@@ -0,0 +1,264 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+)
+
+// files     │ main.go
+// ├─ a.go   │ package main
+// └─ b.go   │
+
+// SplitPane shows two layers side by side (HSplit) or one above
+// the other (VSplit) with a divider between them. Children that
+// are Sizable with AutoSize set are resized along with their side.
+type SplitPane struct {
+	Focusable
+	Sizable
+	Nameable
+	Labelable
+
+	// MinFirst and MinSecond are the smallest sizes the divider
+	// can leave to each side, unless the side is collapsed
+	MinFirst, MinSecond int
+	// Geometry is used to find the pane on screen when the
+	// divider is dragged with the mouse, the pane must be tracked
+	Geometry *Geometry
+	OnResize func(first, second int)
+
+	first, second wind.Layer
+	vertical      bool
+	pos           int
+	length        int
+	collapsed     [2]bool
+	sizes         [2]int
+
+	group   ContainerGroup
+	divider Group
+	groups  [2]Group
+}
+
+// HSplit puts left and right side by side
+func HSplit(w, h int, left, right wind.Layer) *SplitPane {
+	return newSplitPane(w, h, left, right, false)
+}
+
+// VSplit puts top above bottom
+func VSplit(w, h int, top, bottom wind.Layer) *SplitPane {
+	return newSplitPane(w, h, top, bottom, true)
+}
+
+func newSplitPane(w, h int, first, second wind.Layer, vertical bool) *SplitPane {
+	return &SplitPane{
+		Sizable:  Sizable{w: w, h: h},
+		first:    first,
+		second:   second,
+		vertical: vertical,
+		pos:      -1,
+	}
+}
+
+// Position is the size of the first side,
+// it is negative until the pane is rendered.
+func (sp *SplitPane) Position() int {
+	return sp.pos
+}
+
+// SetPosition moves the divider, the position is
+// kept within the minimum sizes when rendered.
+func (sp *SplitPane) SetPosition(pos int) {
+	sp.collapsed = [2]bool{}
+	sp.pos = max(pos, 0)
+	sp.updateGroup()
+}
+
+func (sp *SplitPane) Move(delta int) {
+	if sp.collapsed[0] {
+		sp.SetPosition(delta)
+	} else if sp.collapsed[1] {
+		sp.SetPosition(sp.length + delta)
+	} else {
+		sp.SetPosition(sp.pos + delta)
+	}
+	sp.clampPosition()
+}
+
+func (sp *SplitPane) Collapsed() (first, second bool) {
+	return sp.collapsed[0], sp.collapsed[1]
+}
+
+// ToggleFirst hides the first side or shows it again,
+// only one side can be collapsed at a time.
+func (sp *SplitPane) ToggleFirst() {
+	sp.collapsed = [2]bool{!sp.collapsed[0], false}
+	sp.updateGroup()
+}
+
+func (sp *SplitPane) ToggleSecond() {
+	sp.collapsed = [2]bool{false, !sp.collapsed[1]}
+	sp.updateGroup()
+}
+
+// Drag moves the divider to x, y relative to the pane
+func (sp *SplitPane) Drag(x, y int) {
+	if sp.vertical {
+		sp.SetPosition(y)
+	} else {
+		sp.SetPosition(x)
+	}
+	sp.clampPosition()
+}
+
+// clampPosition keeps the arrow keys from moving
+// the divider past where it can be drawn
+func (sp *SplitPane) clampPosition() {
+	if sp.length > 0 {
+		sp.layout(sp.length)
+	}
+}
+
+// layout splits length, the space left by the divider, between the two sides
+func (sp *SplitPane) layout(length int) (int, int) {
+	if sp.pos < 0 {
+		sp.pos = length / 2
+	} else if sp.length > 0 && length != sp.length {
+		// keeps the proportions when the pane is resized
+		sp.pos = sp.pos * length / sp.length
+	}
+	sp.length = length
+
+	sp.pos = max(min(sp.pos, length-sp.MinSecond), sp.MinFirst)
+	sp.pos = clamp(sp.pos, 0, length)
+
+	switch {
+	case sp.collapsed[0]:
+		return 0, length
+	case sp.collapsed[1]:
+		return length, 0
+	}
+	return sp.pos, length - sp.pos
+}
+
+func (sp *SplitPane) Render(canvas wind.Canvas) {
+	defer sp.drawLabel(canvas)
+	sp.SetSize(canvas.Dimension())
+	w, h := canvas.Dimension()
+
+	var fg uint16 = 0
+	var bg uint16 = 0
+	if sp.IsFocused() {
+		bg = uint16(term.ColorRed)
+	}
+
+	if sp.vertical {
+		a, b := sp.layout(max(h-1, 0))
+		renderSide(sp.first, subCanvas(canvas, 0, 0, w, a))
+		for x := 0; x < w; x++ {
+			canvas.Draw(x, a, '─', fg, bg)
+		}
+		renderSide(sp.second, subCanvas(canvas, 0, a+1, w, b))
+		sp.resized(a, b)
+	} else {
+		a, b := sp.layout(max(w-1, 0))
+		renderSide(sp.first, subCanvas(canvas, 0, 0, a, h))
+		for y := 0; y < h; y++ {
+			canvas.Draw(a, y, '│', fg, bg)
+		}
+		renderSide(sp.second, subCanvas(canvas, a+1, 0, b, h))
+		sp.resized(a, b)
+	}
+}
+
+type resizable interface {
+	SetSize(w, h int)
+}
+
+func renderSide(layer wind.Layer, canvas wind.Canvas) {
+	if layer == nil || canvas.Width() == 0 || canvas.Height() == 0 {
+		return
+	}
+	if r, ok := layer.(resizable); ok {
+		r.SetSize(canvas.Dimension())
+	}
+	layer.Render(canvas)
+}
+
+func (sp *SplitPane) resized(a, b int) {
+	if sp.sizes == [2]int{a, b} {
+		return
+	}
+	sp.sizes = [2]int{a, b}
+	if sp.OnResize != nil {
+		sp.OnResize(a, b)
+	}
+}
+
+// FocusGroup puts the divider between the groups of the sides,
+// a collapsed side is taken out.
+func (sp *SplitPane) FocusGroup() Group {
+	if sp.group == nil {
+		sp.groups = [2]Group{ExtractGroup(sp.first), ExtractGroup(sp.second)}
+		sp.divider = CompGroup(sp)
+		if sp.vertical {
+			sp.group = YGroup(sp.divider)
+		} else {
+			sp.group = XGroup(sp.divider)
+		}
+		sp.updateGroup()
+	}
+	return sp.group
+}
+
+func (sp *SplitPane) updateGroup() {
+	if sp.group == nil {
+		return
+	}
+	for i, g := range sp.groups {
+		if g == NilGroup {
+			continue
+		}
+		shown := g.Parent() == sp.group
+		switch {
+		case sp.collapsed[i] && shown:
+			sp.group.Remove(g)
+		case !sp.collapsed[i] && !shown && i == 0:
+			sp.group.Insert(0, g)
+		case !sp.collapsed[i] && !shown:
+			sp.group.Append(g)
+		}
+	}
+}
+
+// Control moves the divider with the arrow keys or the mouse,
+// 1 and 2 collapse a side, Enter or Esc is done.
+func (sp *SplitPane) Control(flow *control.Flow) {
+	back, forth := term.KeyArrowLeft, term.KeyArrowRight
+	if sp.vertical {
+		back, forth = term.KeyArrowUp, term.KeyArrowDown
+	}
+	runModal(flow, func(e term.Event) bool {
+		if e.Type == term.EventMouse && e.Key == term.MouseLeft && sp.Geometry != nil {
+			if rect, ok := sp.Geometry.Rect(sp); ok {
+				sp.Drag(e.MouseX-rect.X, e.MouseY-rect.Y)
+			}
+			return false
+		}
+		if e.Type != term.EventKey {
+			return false
+		}
+		switch {
+		case e.Key == term.KeyEnter, e.Key == term.KeyEsc:
+			return true
+		case e.Key == back:
+			sp.Move(-1)
+		case e.Key == forth:
+			sp.Move(1)
+		case e.Ch == '1':
+			sp.ToggleFirst()
+		case e.Ch == '2':
+			sp.ToggleSecond()
+		}
+		return false
+	})
+}
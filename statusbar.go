@@ -0,0 +1,164 @@
+package severe
+
+import (
+	"fmt"
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/wind"
+)
+
+// NORMAL │ editor          main.go          3:12
+
+// Segment is a piece of text in a StatusBar. It keeps its text
+// until Set is called, which can be done from any goroutine.
+type Segment struct {
+	asyncState
+	text string
+}
+
+func NewSegment(text string) *Segment {
+	return &Segment{text: text}
+}
+
+func (seg *Segment) Set(text string) {
+	seg.Lock()
+	if seg.text == text {
+		seg.Unlock()
+		return
+	}
+	seg.text = text
+	seg.Unlock()
+	seg.changed()
+}
+
+func (seg *Segment) Setf(format string, args ...interface{}) {
+	seg.Set(fmt.Sprintf(format, args...))
+}
+
+func (seg *Segment) Text() string {
+	seg.Lock()
+	defer seg.Unlock()
+	return seg.text
+}
+
+func (seg *Segment) rendered() string {
+	seg.Lock()
+	defer seg.Unlock()
+	seg.dirty = false
+	return seg.text
+}
+
+// FocusSegment shows the name of the focused component
+func FocusSegment(foc *Focuser) *Segment {
+	seg := NewSegment(componentName(foc.Current()))
+	foc.OnFocusChange(func(_, new Component) {
+		seg.Set(componentName(new))
+	})
+	return seg
+}
+
+func componentName(comp Component) string {
+	if named, ok := comp.(NamedComponent); ok {
+		return named.Name()
+	}
+	return ""
+}
+
+// CursorSegment shows the line and column of the cursor in tbox
+func CursorSegment(tbox *Textbox) *Segment {
+	seg := NewSegment("")
+	update := func(x, y int) { seg.Setf("%d:%d", y+1, x+1) }
+	update(tbox.Cursor())
+
+	onMove := tbox.OnCursorMove
+	tbox.OnCursorMove = func(x, y int) {
+		if onMove != nil {
+			onMove(x, y)
+		}
+		update(x, y)
+	}
+	return seg
+}
+
+// StatusBar is a one line layer. When it's too narrow, the center
+// goes first, then the left side is cut short, the right is kept.
+type StatusBar struct {
+	Sizable
+	Left   []*Segment
+	Center []*Segment
+	Right  []*Segment
+	Sep    string
+	Fg, Bg term.Attribute
+}
+
+func NewStatusBar(w int) *StatusBar {
+	return &StatusBar{
+		Sizable: Sizable{w: w, h: 1},
+		Sep:     " │ ",
+		Fg:      term.ColorBlack,
+		Bg:      term.ColorWhite,
+	}
+}
+
+func (bar *StatusBar) AddLeft(segs ...*Segment)   { bar.Left = append(bar.Left, segs...) }
+func (bar *StatusBar) AddCenter(segs ...*Segment) { bar.Center = append(bar.Center, segs...) }
+func (bar *StatusBar) AddRight(segs ...*Segment)  { bar.Right = append(bar.Right, segs...) }
+
+// join leaves out the empty segments
+func (bar *StatusBar) join(segs []*Segment) []rune {
+	var line []rune
+	for _, seg := range segs {
+		text := seg.rendered()
+		if text == "" {
+			continue
+		}
+		if len(line) > 0 {
+			line = append(line, []rune(bar.Sep)...)
+		}
+		line = append(line, []rune(text)...)
+	}
+	return line
+}
+
+func (bar *StatusBar) Render(canvas wind.Canvas) {
+	bar.SetSize(canvas.Dimension())
+	w := canvas.Width()
+	left := bar.join(bar.Left)
+	center := bar.join(bar.Center)
+	right := bar.join(bar.Right)
+
+	line := make([]rune, w)
+	for i := range line {
+		line[i] = ' '
+	}
+
+	// one space on each end
+	start := min(1, w)
+	end := max(w-1, start)
+	if len(right) > end-start {
+		right = truncate(right, end-start)
+	}
+	rightX := end - len(right)
+	copy(line[rightX:], right)
+
+	if len(left) > 0 {
+		space := rightX - start
+		if len(right) > 0 {
+			space--
+		}
+		left = truncate(left, max(space, 0))
+		copy(line[start:], left)
+	}
+	leftEnd := start + len(left)
+
+	// centered on the bar if possible, otherwise in the gap
+	// between the sides, and dropped if it doesn't fit
+	if gap := rightX - leftEnd - 2; len(center) > 0 && len(center) <= gap {
+		x := (w - len(center)) / 2
+		x = clamp(x, leftEnd+1, rightX-1-len(center))
+		copy(line[x:], center)
+	}
+
+	for x, c := range line {
+		canvas.Draw(x, 0, c, uint16(bar.Fg), uint16(bar.Bg))
+	}
+}
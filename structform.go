@@ -0,0 +1,306 @@
+package severe
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StructForm makes a Form for the exported fields of the struct
+// that ptr points to, filled in with their current values.
+// Strings and numbers get a TextInput, bools a CheckField, times
+// a Calendar and fields with an enum a SelectField. Fields of other
+// types, like nested structs, slices and pointers, are left out.
+// The struct tag sets the details, a "-" leaves the field out too:
+//
+//	type Config struct {
+//		Name  string `severe:"label=Your name,required"`
+//		Port  int    `severe:"width=6"`
+//		Mode  string `severe:"enum=fast|safe"`
+//		Level int    `severe:"enum=low|high"`
+//		Debug bool
+//	}
+//
+// An integer field with an enum stores the index of the item,
+// and a bool field the second of its two items as true.
+//
+// The values of the form are keyed by the field names,
+// StoreValues writes them back into the struct.
+func StructForm(ptr interface{}) (*Form, error) {
+	v, err := structValue(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	form := NewForm()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := parseFormTag(sf)
+		if !ok {
+			continue
+		}
+		field, validators, err := formField(v.Field(i), tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", sf.Name, err)
+		}
+		if tag.required {
+			validators = append([]Validator{Required}, validators...)
+		}
+		form.Add(sf.Name, tag.label, field, validators...)
+	}
+	return form, nil
+}
+
+// StoreValues writes the values of a form made by StructForm
+// into the struct that ptr points to.
+func StoreValues(ptr interface{}, values map[string]interface{}) error {
+	v, err := structValue(ptr)
+	if err != nil {
+		return err
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		value, found := values[sf.Name]
+		tag, ok := parseFormTag(sf)
+		if !ok || !found {
+			continue
+		}
+		value = enumValue(sf.Type.Kind(), tag.enum, value)
+		if err := setFieldValue(v.Field(i), value); err != nil {
+			return fmt.Errorf("%s: %v", sf.Name, err)
+		}
+	}
+	return nil
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// formType returns false for the types that can't be in a form
+func formType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return t == timeType
+}
+
+func structValue(ptr interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("severe: expected a pointer to a struct, got %T", ptr)
+	}
+	return v.Elem(), nil
+}
+
+type formTag struct {
+	label    string
+	required bool
+	enum     []string
+	width    int
+}
+
+// parseFormTag returns false for the fields that are left out
+func parseFormTag(sf reflect.StructField) (formTag, bool) {
+	tag := formTag{label: sf.Name, width: 20}
+	if sf.PkgPath != "" || !formType(sf.Type) {
+		return tag, false
+	}
+	text := sf.Tag.Get("severe")
+	if text == "-" {
+		return tag, false
+	}
+	for _, opt := range strings.Split(text, ",") {
+		key, value := opt, ""
+		if i := strings.Index(opt, "="); i >= 0 {
+			key, value = opt[:i], opt[i+1:]
+		}
+		switch strings.TrimSpace(key) {
+		case "label":
+			tag.label = value
+		case "required":
+			tag.required = true
+		case "enum":
+			tag.enum = strings.Split(value, "|")
+		case "width":
+			if w, err := strconv.Atoi(value); err == nil {
+				tag.width = w
+			}
+		}
+	}
+	return tag, true
+}
+
+func formField(v reflect.Value, tag formTag) (FormField, []Validator, error) {
+	if v.Type() == timeType {
+		return NewCalendar(v.Interface().(time.Time)), nil, nil
+	}
+
+	var validators []Validator
+	if v.Kind() != reflect.String && v.Kind() != reflect.Bool {
+		validators = append(validators, typeValidator(v.Type(), tag.enum))
+	}
+
+	if len(tag.enum) > 0 {
+		item, err := enumItem(v, tag.enum)
+		if err != nil {
+			return nil, nil, err
+		}
+		w := 0
+		for _, item := range tag.enum {
+			w = max(w, len([]rune(item)))
+		}
+		field := NewSelectField(w+2, min(len(tag.enum), 5), ItemSlice(tag.enum))
+		field.SetValue(item)
+		return field, validators, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return NewCheckField("", v.Bool()), nil, nil
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return NewTextInput(tag.width, fieldText(v)), validators, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported type %v", v.Type())
+}
+
+// fieldText is the text that a field starts with. The String method
+// of numbers (like an enum's) isn't used, the text has to parse back.
+func fieldText(v reflect.Value) string {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	}
+	return v.String()
+}
+
+// enumItem is the item of the enum that the field starts with
+func enumItem(v reflect.Value, enum []string) (string, error) {
+	var i int
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		if len(enum) != 2 {
+			return "", fmt.Errorf("the enum of a bool needs two items, for false and true")
+		}
+		if v.Bool() {
+			i = 1
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i = int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i = int(v.Uint())
+	default:
+		return "", fmt.Errorf("an enum needs a string, integer or bool field, not %v", v.Type())
+	}
+	if i < 0 || i >= len(enum) {
+		return "", nil
+	}
+	return enum[i], nil
+}
+
+// enumValue turns the item of an enum into what a field of the given
+// kind stores: the index for integers, false or true for bools
+func enumValue(kind reflect.Kind, enum []string, value interface{}) interface{} {
+	if len(enum) == 0 || kind == reflect.String {
+		return value
+	}
+	for i, item := range enum {
+		if item == fmt.Sprint(value) {
+			if kind == reflect.Bool {
+				return i == 1
+			}
+			return strconv.Itoa(i)
+		}
+	}
+	return value
+}
+
+// typeValidator checks that the value can be stored in a field of type t
+func typeValidator(t reflect.Type, enum []string) Validator {
+	return func(value interface{}) error {
+		return setFieldValue(reflect.New(t).Elem(), enumValue(t.Kind(), enum, value))
+	}
+}
+
+// setFieldValue stores the value of a field, the text
+// of numbers is parsed and empty text is a zero.
+func setFieldValue(v reflect.Value, value interface{}) error {
+	if b, ok := value.(bool); ok && v.Kind() == reflect.Bool {
+		v.SetBool(b)
+		return nil
+	}
+	if t, ok := value.(time.Time); ok && v.Type() == timeType {
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	text := strings.TrimSpace(fmt.Sprint(value))
+	if text == "" && v.Kind() != reflect.String {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("not a duration, like 1h30m")
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(fmt.Sprint(value))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return numberError(err, "a whole number")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return numberError(err, "a positive whole number")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return numberError(err, "a number")
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+func numberError(err error, expected string) error {
+	if nerr, ok := err.(*strconv.NumError); ok && nerr.Err == strconv.ErrRange {
+		return fmt.Errorf("out of range")
+	}
+	return fmt.Errorf("not %s", expected)
+}
@@ -0,0 +1,384 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"sort"
+	"strconv"
+)
+
+// Name      Size ▲  Modified
+// a.txt        12  2015-01-02
+// b.txt       340  2015-03-04
+
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Column describes a table column. Width is the preferred width,
+// the title decides it when zero. Columns with a Flex share
+// the spare width of the table in proportion to it.
+// Min and Max are ignored when zero.
+type Column struct {
+	Title    string
+	Width    int
+	Min, Max int
+	Flex     int
+	Align    Align
+}
+
+func (col Column) clampWidth(w int) int {
+	if col.Min > 0 && w < col.Min {
+		w = col.Min
+	}
+	if col.Max > 0 && w > col.Max {
+		w = col.Max
+	}
+	return w
+}
+
+// TableRows is the data source of a Table, only the visible rows
+// are asked for when rendering.
+type TableRows interface {
+	RowCount() int
+	Row(i int) []string
+}
+
+// TableSorter can be implemented by data sources that sort themselves,
+// a large or remote dataset for instance. Otherwise the Table sorts
+// an index of the rows, which reads every row once.
+type TableSorter interface {
+	SortRows(col int, desc bool)
+}
+
+type RowSlice [][]string
+
+func (rows RowSlice) RowCount() int      { return len(rows) }
+func (rows RowSlice) Row(i int) []string { return rows[i] }
+
+type Table struct {
+	Focusable
+	Sizable
+	Nameable
+	Labelable
+
+	Columns []Column
+	Rows    TableRows
+
+	view     *Viewport
+	order    []int
+	sortCol  int
+	sortDesc bool
+}
+
+func NewTable(w, h int, columns []Column, rows TableRows) *Table {
+	tbl := &Table{
+		Sizable: Sizable{w: w, h: h},
+		Columns: columns,
+		Rows:    rows,
+		sortCol: -1,
+	}
+	tbl.view = &Viewport{
+		w: 1,
+		h: max(h-1, 0),
+		bounds: func(_, _ int) (int, int) {
+			return len(tbl.Columns) - 1, tbl.Rows.RowCount()
+		},
+	}
+	return tbl
+}
+
+// layout returns the widths of the columns for a table of the given width
+func (tbl *Table) layout(width int) []int {
+	widths := make([]int, len(tbl.Columns))
+	total := len(tbl.Columns) - 1
+	flex := 0
+	for i, col := range tbl.Columns {
+		w := col.Width
+		if w <= 0 {
+			// leaves room for the sort mark
+			w = len([]rune(col.Title)) + 2
+		}
+		widths[i] = col.clampWidth(w)
+		total += widths[i]
+		flex += col.Flex
+	}
+
+	spare := width - total
+	if spare > 0 && flex > 0 {
+		for i, col := range tbl.Columns {
+			if col.Flex > 0 {
+				widths[i] = col.clampWidth(widths[i] + spare*col.Flex/flex)
+			}
+		}
+	}
+	return widths
+}
+
+// scrollColumns keeps the cursor column in sight and returns how many
+// columns fit from the offset onwards; the viewport counts in columns.
+func (tbl *Table) scrollColumns(widths []int, width int) int {
+	view := tbl.view
+	fits := func(from, to int) bool {
+		total := to - from
+		for _, w := range widths[from : to+1] {
+			total += w
+		}
+		return total <= width
+	}
+	for view.cursX > 0 && !fits(view.offX, view.offX+view.cursX) {
+		view.offX++
+		view.cursX--
+	}
+	n := 1
+	for view.offX+n < len(widths) && fits(view.offX, view.offX+n) {
+		n++
+	}
+	return n
+}
+
+func (tbl *Table) Render(canvas wind.Canvas) {
+	defer tbl.drawLabel(canvas)
+	tbl.SetSize(canvas.Dimension())
+	width, height := canvas.Dimension()
+	if len(tbl.Columns) == 0 || height == 0 {
+		return
+	}
+
+	count := tbl.Rows.RowCount()
+	if tbl.order != nil && len(tbl.order) != count {
+		tbl.sortIndex()
+	}
+
+	widths := tbl.layout(width)
+	tbl.view.SetSize(tbl.scrollColumns(widths, width), height-1)
+	if _, i := tbl.view.Point(); i >= count || tbl.view.offY+tbl.view.h > count {
+		// the data source may have shrunk
+		tbl.SetIndex(min(i, count-1))
+	}
+
+	cursX, cursY := tbl.view.Cursor()
+	offX, offY := tbl.view.Offset()
+
+	header := make([]string, len(tbl.Columns))
+	for i, col := range tbl.Columns {
+		header[i] = col.Title
+		if i == tbl.sortCol {
+			if tbl.sortDesc {
+				header[i] += " ▼"
+			} else {
+				header[i] += " ▲"
+			}
+		}
+	}
+	tbl.drawRow(canvas, 0, offX, widths, header, func(i int) (uint16, uint16) {
+		if i == offX+cursX && tbl.IsFocused() {
+			return uint16(term.AttrBold), uint16(term.ColorBlue)
+		}
+		return uint16(term.AttrBold | term.AttrUnderline), 0
+	})
+
+	endY := min(offY+height-1, count)
+	for y := offY; y < endY; y++ {
+		var bgColor uint16 = 0
+		if tbl.IsFocused() {
+			bgColor = uint16(term.ColorRed)
+		}
+		if y-offY == cursY {
+			bgColor = uint16(term.ColorBlue)
+		}
+		row := tbl.Rows.Row(tbl.rowIndex(y))
+		tbl.drawRow(canvas, y-offY+1, offX, widths, row, func(_ int) (uint16, uint16) {
+			return 0, bgColor
+		})
+	}
+}
+
+// drawRow draws the cells from column offX onwards,
+// color is called with -1 for the gaps between them.
+func (tbl *Table) drawRow(canvas wind.Canvas, y, offX int, widths []int, cells []string, color func(i int) (uint16, uint16)) {
+	x := 0
+	width := canvas.Width()
+	for i := offX; i < len(widths); i++ {
+		if x >= width {
+			return
+		}
+		fg, bg := color(i)
+		if i > offX {
+			_, sepBg := color(-1)
+			canvas.Draw(x, y, ' ', 0, sepBg)
+			x++
+		}
+		text := ""
+		if i < len(cells) {
+			text = cells[i]
+		}
+		for _, c := range alignText(text, widths[i], tbl.Columns[i].Align) {
+			if x >= width {
+				return
+			}
+			canvas.Draw(x, y, c, fg, bg)
+			x++
+		}
+	}
+	_, bg := color(-1)
+	for ; x < width; x++ {
+		canvas.Draw(x, y, ' ', 0, bg)
+	}
+}
+
+func alignText(text string, width int, align Align) []rune {
+	runes := truncate([]rune(text), width)
+	line := make([]rune, width)
+	for i := range line {
+		line[i] = ' '
+	}
+	pad := width - len(runes)
+	switch align {
+	case AlignRight:
+		copy(line[pad:], runes)
+	case AlignCenter:
+		copy(line[pad/2:], runes)
+	default:
+		copy(line, runes)
+	}
+	return line
+}
+
+// rowIndex maps a displayed row to the row of the data source
+func (tbl *Table) rowIndex(i int) int {
+	if tbl.order != nil {
+		return tbl.order[i]
+	}
+	return i
+}
+
+// SortBy sorts the rows by the given column; numbers are compared
+// as numbers. A negative column restores the order of the data source.
+func (tbl *Table) SortBy(col int, desc bool) {
+	tbl.order = nil
+	tbl.sortCol, tbl.sortDesc = col, desc
+	if col < 0 || col >= len(tbl.Columns) {
+		tbl.sortCol = -1
+		return
+	}
+	if sorter, ok := tbl.Rows.(TableSorter); ok {
+		sorter.SortRows(col, desc)
+		return
+	}
+	tbl.sortIndex()
+}
+
+// ToggleSort sorts by the column, or reverses the order
+// if the table is already sorted by it.
+func (tbl *Table) ToggleSort(col int) {
+	if col == tbl.sortCol {
+		tbl.SortBy(col, !tbl.sortDesc)
+	} else {
+		tbl.SortBy(col, false)
+	}
+}
+
+func (tbl *Table) SortColumn() (col int, desc bool) {
+	return tbl.sortCol, tbl.sortDesc
+}
+
+func (tbl *Table) sortIndex() {
+	col, desc := tbl.sortCol, tbl.sortDesc
+	count := tbl.Rows.RowCount()
+	keys := make([]string, count)
+	tbl.order = make([]int, count)
+	for i := range keys {
+		if row := tbl.Rows.Row(i); col < len(row) {
+			keys[i] = row[col]
+		}
+		tbl.order[i] = i
+	}
+	sort.SliceStable(tbl.order, func(a, b int) bool {
+		if desc {
+			return lessCell(keys[tbl.order[b]], keys[tbl.order[a]])
+		}
+		return lessCell(keys[tbl.order[a]], keys[tbl.order[b]])
+	})
+}
+
+func lessCell(a, b string) bool {
+	x, errX := strconv.ParseFloat(a, 64)
+	y, errY := strconv.ParseFloat(b, 64)
+	if errX == nil && errY == nil {
+		return x < y
+	}
+	return a < b
+}
+
+// SelectedRow returns the index of the selected row in the data source
+func (tbl *Table) SelectedRow() (int, []string) {
+	_, i := tbl.view.Point()
+	if i < 0 || i >= tbl.Rows.RowCount() {
+		return -1, nil
+	}
+	i = tbl.rowIndex(i)
+	return i, tbl.Rows.Row(i)
+}
+
+// SelectedColumn is the column under the cursor, the one sorted by on a keypress
+func (tbl *Table) SelectedColumn() int {
+	x, _ := tbl.view.Point()
+	return x
+}
+
+// SetIndex moves the cursor to the i-th displayed row
+func (tbl *Table) SetIndex(i int) {
+	tbl.view.SetPointY(i)
+}
+
+func (tbl *Table) SelectUp()    { tbl.view.CursorUp() }
+func (tbl *Table) SelectDown()  { tbl.view.CursorDown() }
+func (tbl *Table) ScrollLeft()  { tbl.view.CursorLeft() }
+func (tbl *Table) ScrollRight() { tbl.view.CursorRight() }
+
+func (tbl *Table) PageUp() {
+	_, i := tbl.view.Point()
+	tbl.SetIndex(i - max(tbl.view.h-1, 1))
+}
+
+func (tbl *Table) PageDown() {
+	_, i := tbl.view.Point()
+	tbl.SetIndex(i + max(tbl.view.h-1, 1))
+}
+
+func (tbl *Table) Choose(flow *control.Flow) (int, []string) {
+	tbl.Control(flow)
+	return tbl.SelectedRow()
+}
+
+func (tbl *Table) DefaultKeys() control.Keymap {
+	return control.Keymap{
+		term.KeyArrowUp:    func(_ *control.Flow) { tbl.SelectUp() },
+		term.KeyArrowDown:  func(_ *control.Flow) { tbl.SelectDown() },
+		term.KeyArrowLeft:  func(_ *control.Flow) { tbl.ScrollLeft() },
+		term.KeyArrowRight: func(_ *control.Flow) { tbl.ScrollRight() },
+		term.KeyPgup:       func(_ *control.Flow) { tbl.PageUp() },
+		term.KeyPgdn:       func(_ *control.Flow) { tbl.PageDown() },
+		term.KeyHome:       func(_ *control.Flow) { tbl.SetIndex(0) },
+		term.KeyEnd:        func(_ *control.Flow) { tbl.SetIndex(tbl.Rows.RowCount() - 1) },
+	}
+}
+
+// Control moves the selection with the arrow keys,
+// s sorts by the selected column.
+func (tbl *Table) Control(flow *control.Flow) {
+	keymap := tbl.DefaultKeys()
+	flow.TermTransfer(control.Opts{}, func(flow *control.Flow, e term.Event) {
+		if fn, ok := keymap[e.Key]; ok && e.Ch == 0 {
+			fn(flow)
+		} else if e.Ch == 's' {
+			tbl.ToggleSort(tbl.SelectedColumn())
+		}
+	})
+}
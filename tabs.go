@@ -0,0 +1,181 @@
+package severe
+
+import (
+	"fmt"
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+	"github.com/nvlled/wind/size"
+)
+
+//  1:files │ 2:search │ 3:help
+// ───────────────────────────────
+// (active page)
+
+// Tabs shows one page at a time under a strip of tab titles.
+// PgUp/PgDn or the number keys switch between the pages
+// (termbox doesn't report Ctrl with PgUp/PgDn).
+// Its focus group only contains the tab strip and the active page.
+type Tabs struct {
+	Focusable
+	Nameable
+	Labelable
+	titles   []string
+	pages    []wind.Layer
+	groups   []Group
+	active   int
+	group    ContainerGroup
+	slot     ContainerGroup
+	OnChange func(index int)
+}
+
+func NewTabs() *Tabs {
+	return &Tabs{}
+}
+
+// AddTab returns the index of the new page.
+func (tabs *Tabs) AddTab(title string, page wind.Layer) int {
+	tabs.titles = append(tabs.titles, title)
+	tabs.pages = append(tabs.pages, page)
+	tabs.groups = append(tabs.groups, ExtractGroup(page))
+	i := len(tabs.pages) - 1
+	if i == tabs.active && tabs.slot != nil {
+		tabs.showPage(i)
+	}
+	return i
+}
+
+func (tabs *Tabs) Count() int {
+	return len(tabs.pages)
+}
+
+func (tabs *Tabs) Active() (int, wind.Layer) {
+	if tabs.active >= len(tabs.pages) {
+		return -1, nil
+	}
+	return tabs.active, tabs.pages[tabs.active]
+}
+
+func (tabs *Tabs) Select(i int) {
+	if i < 0 || i >= len(tabs.pages) || i == tabs.active {
+		return
+	}
+	old := tabs.active
+	tabs.active = i
+	if tabs.slot != nil {
+		tabs.swapPage(old, i)
+	}
+	if tabs.OnChange != nil {
+		tabs.OnChange(i)
+	}
+}
+
+func (tabs *Tabs) Next() {
+	tabs.Select(tabs.active + 1)
+}
+
+func (tabs *Tabs) Prev() {
+	tabs.Select(tabs.active - 1)
+}
+
+// the groups are replaced in one go, so that the focus
+// goes to the new page if it was in the old one
+func (tabs *Tabs) swapPage(old, i int) {
+	oldg, newg := tabs.groups[old], tabs.groups[i]
+	switch {
+	case oldg == NilGroup:
+		tabs.showPage(i)
+	case newg == NilGroup:
+		tabs.slot.Remove(oldg)
+	default:
+		tabs.slot.Replace(oldg, newg)
+	}
+}
+
+func (tabs *Tabs) showPage(i int) {
+	if g := tabs.groups[i]; g != NilGroup {
+		tabs.slot.Append(g)
+	}
+}
+
+// FocusGroup is the tab strip above the group of the
+// active page, which is swapped when the page changes.
+func (tabs *Tabs) FocusGroup() Group {
+	if tabs.group == nil {
+		tabs.slot = YGroup()
+		tabs.group = YGroup(CompGroup(tabs), tabs.slot)
+		if tabs.active < len(tabs.pages) {
+			tabs.showPage(tabs.active)
+		}
+	}
+	return tabs.group
+}
+
+func (tabs *Tabs) layout() wind.Layer {
+	strip := &tabStrip{tabs}
+	if _, page := tabs.Active(); page != nil {
+		return wind.Vlayer(strip, wind.LineH('─'), page)
+	}
+	return wind.Vlayer(strip, wind.LineH('─'))
+}
+
+func (tabs *Tabs) Width() size.T  { return tabs.layout().Width() }
+func (tabs *Tabs) Height() size.T { return tabs.layout().Height() }
+
+func (tabs *Tabs) Render(canvas wind.Canvas) {
+	defer tabs.drawLabel(canvas)
+	tabs.layout().Render(canvas)
+}
+
+func (tabs *Tabs) Control(flow *control.Flow) {
+	flow.TermTransfer(control.Opts{}, func(_ *control.Flow, e term.Event) {
+		switch {
+		case e.Key == term.KeyPgup:
+			tabs.Prev()
+		case e.Key == term.KeyPgdn:
+			tabs.Next()
+		case e.Ch >= '1' && e.Ch <= '9':
+			tabs.Select(int(e.Ch - '1'))
+		}
+	})
+}
+
+type tabStrip struct {
+	tabs *Tabs
+}
+
+func (strip *tabStrip) text() string {
+	text := ""
+	for i, title := range strip.tabs.titles {
+		if i > 0 {
+			text += "│"
+		}
+		text += fmt.Sprintf(" %d:%s ", i+1, title)
+	}
+	return text
+}
+
+func (strip *tabStrip) Width() size.T  { return size.Const(len([]rune(strip.text()))) }
+func (strip *tabStrip) Height() size.T { return size.Const(1) }
+
+func (strip *tabStrip) Render(canvas wind.Canvas) {
+	tabs := strip.tabs
+	x := 0
+	for i, title := range tabs.titles {
+		if i > 0 {
+			canvas.Draw(x, 0, '│', 0, 0)
+			x++
+		}
+		bg := term.ColorDefault
+		if i == tabs.active {
+			bg = term.ColorBlue
+			if tabs.IsFocused() {
+				bg = term.ColorRed
+			}
+		}
+		for _, c := range fmt.Sprintf(" %d:%s ", i+1, title) {
+			canvas.Draw(x, 0, c, 0, uint16(bg))
+			x++
+		}
+	}
+}
@@ -19,15 +19,20 @@ func (fn bufferFunc) Buffer() [][]rune { return fn() }
 type Textbox struct {
 	Focusable
 	Sizable
+	Nameable
+	Labelable
 	buffer [][]rune
 	view   *Viewport
+	// OnCursorMove is called when the cursor has moved or the
+	// buffer has been replaced, x and y start from zero
+	OnCursorMove func(x, y int)
 }
 
 func NewTextbox(w, h int) *Textbox {
 	tbox := &Textbox{
 		Sizable: Sizable{w: w, h: h},
 		buffer:  nil,
-		view:    new(Viewport),
+		view:    &Viewport{w: w, h: h},
 	}
 	tbox.view.bounds = makeBufferBounds(tbox)
 	return tbox
@@ -60,9 +65,28 @@ func (tbox *Textbox) SetBuffer(text string) {
 	}
 	buffer = append(buffer, []rune("\n"))
 	tbox.buffer = buffer
+	tbox.cursorMoved()
+}
+
+func (tbox *Textbox) cursorMoved() {
+	if tbox.OnCursorMove != nil {
+		tbox.OnCursorMove(tbox.Cursor())
+	}
+}
+
+// watchCursor returns a function that calls OnCursorMove
+// if the cursor has moved since, for use with defer
+func (tbox *Textbox) watchCursor() func() {
+	x, y := tbox.Cursor()
+	return func() {
+		if x_, y_ := tbox.Cursor(); x_ != x || y_ != y {
+			tbox.cursorMoved()
+		}
+	}
 }
 
 func (tbox *Textbox) Render(canvas wind.Canvas) {
+	defer tbox.drawLabel(canvas)
 	if len(tbox.buffer) == 0 {
 		return
 	}
@@ -83,17 +107,27 @@ func (tbox *Textbox) Render(canvas wind.Canvas) {
 		endX := min(ox+w, len(row))
 		if ox < len(row) {
 			for x, c := range row[ox:endX] {
-				canvas.Draw(x, y, c, 0, uint16(bg))
+				canvas.Draw(x, y, visibleRune(c), 0, uint16(bg))
 			}
 		}
 	}
 	cx, cy := view.Cursor()
 	if oy+cy < len(tbox.buffer) && ox+cx < len(tbox.buffer[oy+cy]) {
-		canvas.Draw(cx, cy, tbox.buffer[oy+cy][ox+cx], 0, uint16(term.ColorBlue))
+		c := visibleRune(tbox.buffer[oy+cy][ox+cx])
+		canvas.Draw(cx, cy, c, 0, uint16(term.ColorBlue))
 	}
 }
 
+// the line terminators are drawn as spaces
+func visibleRune(c rune) rune {
+	if c == '\n' {
+		return ' '
+	}
+	return c
+}
+
 func (tbox *Textbox) InsertChar(ch rune) {
+	defer tbox.watchCursor()()
 	x, y := tbox.view.Point()
 	line := tbox.buffer[y]
 	rest := line[x:]
@@ -106,6 +140,7 @@ func (tbox *Textbox) InsertChar(ch rune) {
 }
 
 func (tbox *Textbox) InsertNewline() {
+	defer tbox.watchCursor()()
 	// *** Assumes line has a line terminator in it
 	//     that is to say, ∀line, len(line) >= 1 and ('\n' ∈ line)
 	x, y := tbox.view.Point()
@@ -127,6 +162,7 @@ func (tbox *Textbox) InsertNewline() {
 }
 
 func (tbox *Textbox) DeleteBack() {
+	defer tbox.watchCursor()()
 	x, y := tbox.view.Point()
 	line := tbox.buffer[y]
 	if x > 0 {
@@ -151,32 +187,64 @@ func (tbox *Textbox) DeleteBack() {
 	}
 }
 
-func (tbox *Textbox) CursorUp()    { tbox.view.CursorUp() }
-func (tbox *Textbox) CursorDown()  { tbox.view.CursorDown() }
-func (tbox *Textbox) CursorLeft()  { tbox.view.CursorLeft() }
-func (tbox *Textbox) CursorRight() { tbox.view.CursorRight() }
+func (tbox *Textbox) CursorUp()    { tbox.moveCursor(tbox.view.CursorUp) }
+func (tbox *Textbox) CursorDown()  { tbox.moveCursor(tbox.view.CursorDown) }
+func (tbox *Textbox) CursorLeft()  { tbox.moveCursor(tbox.view.CursorLeft) }
+func (tbox *Textbox) CursorRight() { tbox.moveCursor(tbox.view.CursorRight) }
+
+func (tbox *Textbox) moveCursor(move func()) {
+	defer tbox.watchCursor()()
+	move()
+}
+
+// Cursor returns the position of the cursor in the buffer
+func (tbox *Textbox) Cursor() (x, y int) {
+	return tbox.view.Point()
+}
+
+// Text returns the buffer without the line terminators.
+func (tbox *Textbox) Text() string {
+	var lines []string
+	for _, line := range tbox.buffer {
+		lines = append(lines, strings.TrimSuffix(string(line), "\n"))
+	}
+	// SetBuffer adds an empty line at the end
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HandleKey edits the buffer according to the key event,
+// for use by components that contain a Textbox.
+func (tbox *Textbox) HandleKey(e term.Event) {
+	if tbox.buffer == nil {
+		tbox.SetBuffer("")
+	}
+	if e.Ch != 0 {
+		tbox.InsertChar(e.Ch)
+	} else {
+		switch e.Key {
+		case term.KeyEnter:
+			tbox.InsertNewline()
+		case term.KeyDelete:
+			tbox.DeleteBack()
+		case term.KeySpace:
+			tbox.InsertChar(' ')
+		case term.KeyArrowDown:
+			tbox.CursorDown()
+		case term.KeyArrowRight:
+			tbox.CursorRight()
+		case term.KeyArrowLeft:
+			tbox.CursorLeft()
+		case term.KeyArrowUp:
+			tbox.CursorUp()
+		}
+	}
+}
 
 func (tbox *Textbox) Control(flow *control.Flow) {
 	flow.TermTransfer(control.Opts{}, func(_ *control.Flow, e term.Event) {
-		if e.Ch != 0 {
-			tbox.InsertChar(e.Ch)
-		} else {
-			switch e.Key {
-			case term.KeyEnter:
-				tbox.InsertNewline()
-			case term.KeyDelete:
-				tbox.DeleteBack()
-			case term.KeySpace:
-				tbox.InsertChar(' ')
-			case term.KeyArrowDown:
-				tbox.CursorDown()
-			case term.KeyArrowRight:
-				tbox.CursorRight()
-			case term.KeyArrowLeft:
-				tbox.CursorLeft()
-			case term.KeyArrowUp:
-				tbox.CursorUp()
-			}
-		}
+		tbox.HandleKey(e)
 	})
 }
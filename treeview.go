@@ -0,0 +1,320 @@
+package severe
+
+import (
+	term "github.com/nsf/termbox-go"
+	"github.com/nvlled/control"
+	"github.com/nvlled/wind"
+)
+
+// ▾ src
+//   ├─▾ cmd
+//   │ └─  main.go
+//   └─▸ lib
+// ▸ docs
+
+// TreeNode is what a TreeView shows. Children is only called
+// when the node is expanded, so it can be loaded lazily.
+type TreeNode interface {
+	Text() string
+	HasChildren() bool
+	Children() []TreeNode
+}
+
+// TreeItem is a ready-made TreeNode. If Load is set, it is called
+// for the children instead of using Items.
+type TreeItem struct {
+	Label string
+	Items []*TreeItem
+	Load  func() []*TreeItem
+}
+
+func (item *TreeItem) Text() string { return item.Label }
+
+func (item *TreeItem) HasChildren() bool {
+	return len(item.Items) > 0 || item.Load != nil
+}
+
+func (item *TreeItem) Children() []TreeNode {
+	items := item.Items
+	if item.Load != nil {
+		items = item.Load()
+	}
+	nodes := make([]TreeNode, len(items))
+	for i, item := range items {
+		nodes[i] = item
+	}
+	return nodes
+}
+
+type treeEntry struct {
+	node     TreeNode
+	parent   *treeEntry
+	depth    int
+	last     bool
+	expanded bool
+	children []*treeEntry
+}
+
+func newTreeEntries(nodes []TreeNode, parent *treeEntry) []*treeEntry {
+	depth := 0
+	if parent != nil {
+		depth = parent.depth + 1
+	}
+	entries := make([]*treeEntry, len(nodes))
+	for i, node := range nodes {
+		entries[i] = &treeEntry{
+			node:   node,
+			parent: parent,
+			depth:  depth,
+			last:   i == len(nodes)-1,
+		}
+	}
+	return entries
+}
+
+// prefix draws the indentation guides of the entry
+func (entry *treeEntry) prefix() string {
+	if entry.parent == nil {
+		return ""
+	}
+	s := "├─"
+	if entry.last {
+		s = "└─"
+	}
+	for p := entry.parent; p.parent != nil; p = p.parent {
+		if p.last {
+			s = "  " + s
+		} else {
+			s = "│ " + s
+		}
+	}
+	return "  " + s
+}
+
+type TreeView struct {
+	Focusable
+	Sizable
+	Nameable
+	Labelable
+
+	roots   []*treeEntry
+	visible []*treeEntry
+	view    *Viewport
+}
+
+func NewTreeView(w, h int, roots ...TreeNode) *TreeView {
+	tree := &TreeView{
+		Sizable: Sizable{w: w, h: h},
+	}
+	tree.view = &Viewport{
+		w: w,
+		h: h,
+		bounds: func(_, _ int) (int, int) {
+			return 0, len(tree.visible)
+		},
+	}
+	tree.SetRoots(roots...)
+	return tree
+}
+
+// SetRoots replaces the whole tree, everything starts collapsed
+func (tree *TreeView) SetRoots(roots ...TreeNode) {
+	tree.roots = newTreeEntries(roots, nil)
+	tree.view.offY, tree.view.cursY = 0, 0
+	tree.flatten()
+}
+
+func (tree *TreeView) flatten() {
+	tree.visible = tree.visible[:0]
+	var add func(entries []*treeEntry)
+	add = func(entries []*treeEntry) {
+		for _, entry := range entries {
+			tree.visible = append(tree.visible, entry)
+			if entry.expanded {
+				add(entry.children)
+			}
+		}
+	}
+	add(tree.roots)
+
+	_, i := tree.view.Point()
+	tree.SetIndex(i)
+}
+
+func (tree *TreeView) Render(canvas wind.Canvas) {
+	defer tree.drawLabel(canvas)
+	tree.SetSize(canvas.Dimension())
+	tree.view.SetSize(tree.Size())
+
+	_, cursY := tree.view.Cursor()
+	_, offY := tree.view.Offset()
+	_, h := tree.view.Size()
+
+	endY := min(offY+h, len(tree.visible))
+	for y, entry := range tree.visible[offY:endY] {
+		var bgColor uint16 = 0
+		if tree.IsFocused() {
+			bgColor = uint16(term.ColorRed)
+		}
+		if y == cursY {
+			bgColor = uint16(term.ColorBlue)
+		}
+
+		mark := "  "
+		if entry.node.HasChildren() {
+			mark = "▸ "
+			if entry.expanded {
+				mark = "▾ "
+			}
+		}
+
+		x := 0
+		for _, c := range entry.prefix() + mark {
+			canvas.Draw(x, y, c, uint16(term.ColorBlack|term.AttrBold), bgColor)
+			x++
+		}
+		for _, c := range entry.node.Text() {
+			canvas.Draw(x, y, c, 0, bgColor)
+			x++
+		}
+		for ; x < canvas.Width(); x++ {
+			canvas.Draw(x, y, ' ', 0, bgColor)
+		}
+	}
+}
+
+func (tree *TreeView) selected() *treeEntry {
+	_, i := tree.view.Point()
+	if i < 0 || i >= len(tree.visible) {
+		return nil
+	}
+	return tree.visible[i]
+}
+
+// Selected returns the node under the cursor, nil if the tree is empty
+func (tree *TreeView) Selected() TreeNode {
+	if entry := tree.selected(); entry != nil {
+		return entry.node
+	}
+	return nil
+}
+
+// Depth returns how deep the selected node is, roots are at 0
+func (tree *TreeView) Depth() int {
+	if entry := tree.selected(); entry != nil {
+		return entry.depth
+	}
+	return -1
+}
+
+func (tree *TreeView) Expand() {
+	entry := tree.selected()
+	if entry == nil || entry.expanded || !entry.node.HasChildren() {
+		return
+	}
+	if entry.children == nil {
+		entry.children = newTreeEntries(entry.node.Children(), entry)
+	}
+	entry.expanded = true
+	tree.flatten()
+}
+
+func (tree *TreeView) Collapse() {
+	entry := tree.selected()
+	if entry == nil || !entry.expanded {
+		return
+	}
+	entry.expanded = false
+	tree.flatten()
+}
+
+func (tree *TreeView) Toggle() {
+	if entry := tree.selected(); entry != nil && entry.expanded {
+		tree.Collapse()
+	} else {
+		tree.Expand()
+	}
+}
+
+// Reload asks the selected node for its children again
+// the next time it's expanded, or right away if it is.
+func (tree *TreeView) Reload() {
+	entry := tree.selected()
+	if entry == nil {
+		return
+	}
+	entry.children = nil
+	if entry.expanded {
+		entry.expanded = false
+		tree.Expand()
+	}
+}
+
+// SelectParent moves the cursor to the parent of the selected node
+func (tree *TreeView) SelectParent() {
+	if entry := tree.selected(); entry != nil && entry.parent != nil {
+		tree.selectEntry(entry.parent)
+	}
+}
+
+func (tree *TreeView) selectEntry(entry *treeEntry) {
+	for i, e := range tree.visible {
+		if e == entry {
+			tree.SetIndex(i)
+			return
+		}
+	}
+}
+
+func (tree *TreeView) SelectUp()   { tree.view.CursorUp() }
+func (tree *TreeView) SelectDown() { tree.view.CursorDown() }
+
+// SetIndex moves the cursor to the i-th visible node
+func (tree *TreeView) SetIndex(i int) {
+	tree.view.SetPointY(i)
+}
+
+func (tree *TreeView) PageUp() {
+	_, i := tree.view.Point()
+	tree.SetIndex(i - max(tree.view.h-1, 1))
+}
+
+func (tree *TreeView) PageDown() {
+	_, i := tree.view.Point()
+	tree.SetIndex(i + max(tree.view.h-1, 1))
+}
+
+func (tree *TreeView) Choose(flow *control.Flow) TreeNode {
+	tree.Control(flow)
+	return tree.Selected()
+}
+
+func (tree *TreeView) DefaultKeys() control.Keymap {
+	return control.Keymap{
+		term.KeyArrowUp:   func(_ *control.Flow) { tree.SelectUp() },
+		term.KeyArrowDown: func(_ *control.Flow) { tree.SelectDown() },
+		term.KeyPgup:      func(_ *control.Flow) { tree.PageUp() },
+		term.KeyPgdn:      func(_ *control.Flow) { tree.PageDown() },
+		term.KeyHome:      func(_ *control.Flow) { tree.SetIndex(0) },
+		term.KeyEnd:       func(_ *control.Flow) { tree.SetIndex(len(tree.visible) - 1) },
+		term.KeySpace:     func(_ *control.Flow) { tree.Toggle() },
+		term.KeyArrowRight: func(_ *control.Flow) {
+			if entry := tree.selected(); entry != nil && entry.expanded && len(entry.children) > 0 {
+				tree.SelectDown()
+			} else {
+				tree.Expand()
+			}
+		},
+		term.KeyArrowLeft: func(_ *control.Flow) {
+			if entry := tree.selected(); entry != nil && entry.expanded {
+				tree.Collapse()
+			} else {
+				tree.SelectParent()
+			}
+		},
+	}
+}
+
+func (tree *TreeView) Control(flow *control.Flow) {
+	flow.TermSwitch(control.Opts{}, tree.DefaultKeys())
+}
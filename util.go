@@ -7,6 +7,41 @@ func min(x, y int) int {
 	return y
 }
 
+func max(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func clamp(x, lo, hi int) int {
+	if x < lo {
+		return lo
+	}
+	if x > hi {
+		return hi
+	}
+	return x
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// truncate cuts the text to n runes, the last one is an ellipsis
+func truncate(text []rune, n int) []rune {
+	if len(text) <= n {
+		return text
+	}
+	if n <= 1 {
+		return text[:n]
+	}
+	return append(text[:n-1:n-1], '…')
+}
+
 //huh....
 func copyLine(line []rune) []rune {
 	line_ := make([]rune, len(line))
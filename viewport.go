@@ -127,6 +127,23 @@ func (view *Viewport) SetCursorY(y int) {
 	view.FocusCursor()
 }
 
+// SetPointY moves the cursor to row y, scrolling no more than needed
+func (view *Viewport) SetPointY(y int) {
+	_, boundsY := view.bounds(view.offX+view.cursX, y)
+	if boundsY <= 0 {
+		view.offY, view.cursY = 0, 0
+		return
+	}
+	y = clamp(y, 0, boundsY-1)
+	view.offY = min(view.offY, max(boundsY-view.h, 0))
+	if y < view.offY {
+		view.offY = y
+	} else if view.h > 0 && y >= view.offY+view.h {
+		view.offY = y - view.h + 1
+	}
+	view.cursY = y - view.offY
+}
+
 func (view *Viewport) repositionCursor() {
 	boundsX, boundsY := view.pointBounds()
 	if view.w > 1 && view.offX+view.cursX >= boundsX {